@@ -1,77 +1,132 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
+	_ "embed"
+	"flag"
 	"fmt"
-	"github.com/dissipative/ribosome/internal/parser"
 	"go/format"
+	"io"
 	"log"
 	"os"
 	"text/template"
-	"time"
 
 	"github.com/dissipative/ribosome/pkg/sequence"
 	"github.com/jlaffaye/ftp"
 )
 
 const (
-	ftpAddr    = "ftp.ncbi.nih.gov:21"
-	ftpPath    = "entrez/misc/data/gc.prt"
-	anonymous  = "anonymous"
-	outputFile = "pkg/sequence/codon_tables.go"
+	ftpAddr   = "ftp.ncbi.nih.gov:21"
+	ftpPath   = "entrez/misc/data/gc.prt"
+	anonymous = "anonymous"
+
+	defaultInputFile  = "internal/ncbi/gc.prt"
+	defaultOutputFile = "pkg/sequence/codon_tables.go"
 )
 
+//go:embed tables.tmpl
+var tablesTemplate string
+
+// example runs (from the repository root):
+//
+//	go run ./cmd/codon_tables_gen                 # regenerate from the vendored gc.prt
+//	go run ./cmd/codon_tables_gen --download      # refresh the vendored gc.prt from NCBI first
+//	go run ./cmd/codon_tables_gen --verify        # fail if the generated file is out of date
 func main() {
-	// Download codon tables from ftp://ftp.ncbi.nih.gov/entrez/misc/data/gc.prt
+	var inputFile string
+	var outputFile string
+	var download bool
+	var verify bool
+	flag.StringVar(&inputFile, "input", defaultInputFile, "Path to NCBI gc.prt file")
+	flag.StringVar(&outputFile, "output", defaultOutputFile, "Path to generated Go file")
+	flag.BoolVar(&download, "download", false, "Download gc.prt from "+ftpAddr+" into --input before generating")
+	flag.BoolVar(&verify, "verify", false, "Check that --output is up to date instead of writing it")
+	flag.Parse()
+
+	if download {
+		err := downloadGCPrt(inputFile)
+		if err != nil {
+			log.Fatalf("error downloading gc.prt: %v\n", err)
+		}
+		fmt.Printf("Downloaded %s\n", inputFile)
+	}
+
+	generated, err := generateFile(inputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if verify {
+		current, err := os.ReadFile(outputFile)
+		if err != nil {
+			log.Fatalf("error reading %s: %v\n", outputFile, err)
+		}
+		if !bytes.Equal(current, generated) {
+			log.Fatalf("%s is out of date, run go generate\n", outputFile)
+		}
+
+		fmt.Printf("%s is up to date\n", outputFile)
+		return
+	}
+
+	// Write the generated code to the tables.go file
+	err = os.WriteFile(outputFile, generated, 0o644)
+	if err != nil {
+		log.Fatalf("error writing to %s: %v\n", outputFile, err)
+	}
+
+	fmt.Printf("Generated %s\n", outputFile)
+}
+
+// downloadGCPrt fetches ftp://ftp.ncbi.nih.gov/entrez/misc/data/gc.prt into filename.
+func downloadGCPrt(filename string) error {
 	conn, err := ftp.Dial(ftpAddr)
 	if err != nil {
-		log.Fatalf("error connecting to %s: %v\n", ftpAddr, err)
+		return fmt.Errorf("error connecting to %s: %w", ftpAddr, err)
 	}
+	defer conn.Quit()
 
 	err = conn.Login(anonymous, anonymous)
 	if err != nil {
-		log.Fatalf("error at login to %s: %v\n", ftpAddr, err)
+		return fmt.Errorf("error at login to %s: %w", ftpAddr, err)
 	}
 
 	resp, err := conn.Retr(ftpPath)
 	if err != nil {
-		log.Fatalf("error downloading gc.prt: %v\n", err)
+		return err
 	}
-
 	defer resp.Close()
 
+	content, err := io.ReadAll(resp)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, content, 0o644)
+}
+
+// generateFile returns formatted Go code for the tables in gc.prt. The output depends only on the input file.
+func generateFile(inputFile string) ([]byte, error) {
 	// Parse gc.prt content
-	scanner := bufio.NewScanner(resp)
-	tables, err := parser.ParsePRTCodonTables(scanner)
+	tables, err := sequence.ParseCodonTablesFile(inputFile)
 	if err != nil {
-		fmt.Printf("error parsing gc.prt: %v\n", err)
-		return
+		return nil, fmt.Errorf("error parsing gc.prt: %w", err)
 	}
 
 	// Generate Go code for the codon tables
 	var buf bytes.Buffer
 	err = generateCode(&buf, tables)
 	if err != nil {
-		fmt.Printf("error generating Go code: %v\n", err)
-		return
+		return nil, fmt.Errorf("error generating Go code: %w", err)
 	}
 
 	// Format the generated Go code
 	formatted, err := format.Source(buf.Bytes())
 	if err != nil {
-		fmt.Printf("error formatting Go code: %v\n", err)
-		return
+		return nil, fmt.Errorf("error formatting Go code: %w", err)
 	}
 
-	// Write the generated code to the tables.go file
-	err = os.WriteFile(outputFile, formatted, 0o644)
-	if err != nil {
-		fmt.Printf("error writing to %s: %v\n", outputFile, err)
-		return
-	}
-
-	fmt.Printf("Generated %s\n", outputFile)
+	return formatted, nil
 }
 
 func generateCode(wr *bytes.Buffer, tables []sequence.CodonTable) error {
@@ -82,12 +137,9 @@ func generateCode(wr *bytes.Buffer, tables []sequence.CodonTable) error {
 		"add": func(a, b int) int {
 			return a + b
 		},
-		"date": func() string {
-			return fmt.Sprintf(" %s", time.Now().Format("2006-01-02 15:04"))
-		},
 	}
 
-	tmpl, err := template.New("tables.tmpl").Funcs(tmplFuncs).ParseFiles("cmd/codon_tables_gen/tables.tmpl")
+	tmpl, err := template.New("tables.tmpl").Funcs(tmplFuncs).Parse(tablesTemplate)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGenerateFile(t *testing.T) {
+	first, err := generateFile("../../" + defaultInputFile)
+	if err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+
+	second, err := generateFile("../../" + defaultInputFile)
+	if err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Errorf("generateFile() is not reproducible for identical input")
+	}
+
+	current, err := os.ReadFile("../../" + defaultOutputFile)
+	if err != nil {
+		t.Fatalf("error reading generated file: %v", err)
+	}
+
+	if !bytes.Equal(current, first) {
+		t.Errorf("%s is out of date, run go generate", defaultOutputFile)
+	}
+}
@@ -15,37 +15,84 @@ import (
 // example runs:
 //
 //	go run cmd/example_app/main.go --input="test/mitochondrions.raw.fas" --table-id=5
+//	go run cmd/example_app/main.go --input="test/mitochondrions.raw.fas" --table=mito-invert
 //	go run cmd/example_app/main.go --tables
+//	go run cmd/example_app/main.go --tables --gc-prt=custom_gc.prt
+//	go run cmd/example_app/main.go --diff=1,mito-invert
 func main() {
 	// Declare flags
 	var inputFile string
 	var formatString string
-	var codonTable int
+	var codonTableID int
+	var codonTableName string
 	var tablesInfo bool
+	var gcPrtFile string
+	var diffTables string
 	flag.StringVar(&inputFile, "input", "", "Input file path")
 	flag.StringVar(&formatString, "format", "fasta", "Format of the input file (fasta or genbank)")
-	flag.IntVar(&codonTable, "table-id", 1, "Codon table used for sequence translation")
+	flag.IntVar(&codonTableID, "table-id", 1, "Codon table used for sequence translation")
+	flag.StringVar(&codonTableName, "table", "", "Codon table ID, name or alias (overrides --table-id)")
 	flag.BoolVar(&tablesInfo, "tables", false, "Display codon tables")
+	flag.StringVar(&gcPrtFile, "gc-prt", "", "Load codon tables from NCBI gc.prt file instead of the built-in ones")
+	flag.StringVar(&diffTables, "diff", "", "Show differences between two codon tables, e.g. --diff=1,5")
 	flag.Parse()
 
+	if gcPrtFile != "" {
+		err := loadCodonTables(gcPrtFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
 	if tablesInfo {
 		printCodonTables()
 		return
 	}
 
+	if diffTables != "" {
+		err := printCodonTablesDiff(diffTables)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if inputFile == "" || formatString == "" {
 		fmt.Println("Please provide both --input and --format flags.")
 		os.Exit(1)
 	}
 
-	err := processSequences(formatString, codonTable, inputFile)
+	var codonTable sequence.CodonTable
+	var err error
+	if codonTableName != "" {
+		codonTable, err = sequence.LookupCodonTable(codonTableName)
+	} else {
+		codonTable, err = sequence.GetCodonTable(codonTableID)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	err = processSequences(formatString, codonTable, inputFile)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
-func processSequences(formatString string, codonTableID int, inputFile string) error {
+func loadCodonTables(filename string) error {
+	tables, err := sequence.ParseCodonTablesFile(filename)
+	if err != nil {
+		return fmt.Errorf("error reading codon tables: %v", err)
+	}
+
+	return sequence.ReplaceCodonTables(tables...)
+}
+
+func processSequences(formatString string, codonTable sequence.CodonTable, inputFile string) error {
 	// Determine file format
 	var format bioio.Format
 	switch formatString {
@@ -61,11 +108,6 @@ func processSequences(formatString string, codonTableID int, inputFile string) e
 		return errors.New("invalid format; please use either 'fasta'/'fas' or 'genbank'/'gb'")
 	}
 
-	codonTable, err := sequence.GetCodonTable(codonTableID)
-	if err != nil {
-		return err
-	}
-
 	// Read sequences from file
 	sequences, err := bioio.ReadFile(inputFile, format)
 	if err != nil {
@@ -86,24 +128,25 @@ func processSequences(formatString string, codonTableID int, inputFile string) e
 func printSequenceInfo(i int, seq bioio.Record, codonTable sequence.CodonTable) error {
 	fmt.Printf("Record %d: %s\n", i+1, seq.ID)
 
-	// Convert to RNASequence
 	dna, err := sequence.NewDNASequence(seq.Sequence)
 	if err != nil {
 		return err
 	}
 
-	// Genbank sequences are reversed?
-	rna := dna.Reverse().Transcribe()
-
-	// Find ORFs with length > 900 bases
-	orfs, err := rna.FindORFs(300, &codonTable)
+	// Find ORFs with length >= 300 codons on both strands, skipping the nested ones
+	orfs, err := dna.FindORFs(sequence.ORFOptions{
+		CodonTable:  &codonTable,
+		MinCodons:   300,
+		LongestOnly: true,
+		Circular:    seq.Circular,
+	})
 	if err != nil {
 		return fmt.Errorf("error finding ORFs: %v", err)
 	}
 	fmt.Printf("  Found %d ORFs\n", len(orfs))
 
 	// Calculate GC content
-	gcContent := sequence.GCContent(rna)
+	gcContent := sequence.GCContent(dna)
 	fmt.Printf("  GC content: %.2f\n", gcContent)
 
 	// Find the longest protein and print
@@ -111,15 +154,23 @@ func printSequenceInfo(i int, seq bioio.Record, codonTable sequence.CodonTable)
 		return nil
 	}
 	sort.Slice(orfs, func(i, j int) bool {
-		return len(orfs[i].ProteinSeq) < len(orfs[j].ProteinSeq)
+		return len(orfs[i].ProteinSeq) > len(orfs[j].ProteinSeq)
 	})
-	fmt.Printf("  Longest protein length: %d bases / %d amino acids\n", orfs[0].End-orfs[0].Start, len(orfs[0].ProteinSeq))
-	fmt.Printf("  Longest protein frame: %d\n", orfs[0].Frame)
+	fmt.Printf("  Longest protein length: %d bases / %d amino acids\n", orfs[0].Codons*3, len(orfs[0].ProteinSeq))
+	fmt.Printf("  Longest protein frame: %d (%s strand)\n", orfs[0].Frame, strandName(orfs[0].Strand))
 	fmt.Printf("  Longest protein sequence: %s\n", orfs[0].ProteinSeq)
 
 	return nil
 }
 
+func strandName(strand sequence.Strand) string {
+	if strand == sequence.ReverseStrand {
+		return "reverse"
+	}
+
+	return "forward"
+}
+
 func printCodonTables() {
 	for _, table := range sequence.CodonTables() {
 		fmt.Printf("Codon Table ID %d:\n", table.ID)
@@ -129,17 +180,30 @@ func printCodonTables() {
 			fmt.Printf("  Description: %s\n", table.Description)
 		}
 
-		// Print start codons
-		fmt.Println("  Start Codons:")
-		for codon, aa := range table.StartCodons {
-			fmt.Printf("    %s: %c", codon, aa)
-		}
+		fmt.Printf("  Aliases: %s\n\n", strings.Join(sequence.CodonTableAliases(table.ID), ", "))
 
-		// Print stop codons
-		fmt.Println("\n  Stop Codons:")
-		for codon, _ := range table.StopCodons {
-			fmt.Printf("    %s", codon)
-		}
-		fmt.Println("\n", strings.Repeat("-", 50))
+		// Print the table as a standard codon grid, start codons are marked with "i"
+		fmt.Print(table.Grid())
+		fmt.Println(strings.Repeat("-", 50))
+	}
+}
+
+func printCodonTablesDiff(tables string) error {
+	names := strings.Split(tables, ",")
+	if len(names) != 2 {
+		return errors.New("please provide two codon tables to compare, e.g. --diff=1,5")
+	}
+
+	from, err := sequence.LookupCodonTable(names[0])
+	if err != nil {
+		return err
 	}
+
+	to, err := sequence.LookupCodonTable(names[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(from.Diff(&to))
+	return nil
 }
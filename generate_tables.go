@@ -1,2 +1,2 @@
-//go:generate go run cmd/codon_tables_gen/generator.go
+//go:generate go run ./cmd/codon_tables_gen
 package ribosome
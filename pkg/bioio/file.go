@@ -2,6 +2,7 @@ package bioio
 
 import (
 	"errors"
+	"io"
 	"os"
 )
 
@@ -19,11 +20,15 @@ func ReadFile(filename string, format Format) ([]Record, error) {
 	}
 	defer file.Close()
 
+	return Read(file, format)
+}
+
+func Read(reader io.Reader, format Format) ([]Record, error) {
 	switch format {
 	case Fasta:
-		return readFASTA(file)
+		return readFASTA(reader)
 	case Genbank:
-		return readGenbank(file)
+		return readGenbank(reader)
 	default:
 		return nil, errors.New("unknown file format")
 	}
@@ -10,9 +10,27 @@ import (
 	"unicode"
 )
 
+// lineScanner can push back the last line, so sections ended by the next keyword don't swallow it
+type lineScanner struct {
+	*bufio.Scanner
+	unread bool
+}
+
+func (s *lineScanner) Scan() bool {
+	if s.unread {
+		s.unread = false
+		return true
+	}
+	return s.Scanner.Scan()
+}
+
+func (s *lineScanner) Unread() {
+	s.unread = true
+}
+
 func readGenbank(reader io.Reader) ([]Record, error) {
 	var sequences []Record
-	scanner := bufio.NewScanner(reader)
+	scanner := &lineScanner{Scanner: bufio.NewScanner(reader)}
 	var currentSeq *Record
 
 	for scanner.Scan() {
@@ -31,6 +49,13 @@ func readGenbank(reader io.Reader) ([]Record, error) {
 			currentSeq = &Record{}
 			currentSeq.ID = fields[1]
 
+			// topology is one of the optional LOCUS fields, linear is assumed when missing
+			for _, field := range fields[2:] {
+				if strings.EqualFold(field, "circular") {
+					currentSeq.Circular = true
+				}
+			}
+
 		case "DEFINITION":
 			if currentSeq == nil {
 				currentSeq = &Record{}
@@ -89,6 +114,7 @@ func readGenbank(reader io.Reader) ([]Record, error) {
 			for scanner.Scan() {
 				line := scanner.Text()
 				if len(line) == 0 || !unicode.IsSpace(rune(line[0])) {
+					scanner.Unread()
 					break
 				}
 
@@ -97,6 +123,13 @@ func readGenbank(reader io.Reader) ([]Record, error) {
 			}
 
 			currentSeq.Taxonomy = strings.TrimSpace(taxonomy)
+
+		case "FEATURES":
+			if currentSeq == nil {
+				currentSeq = &Record{}
+			}
+
+			currentSeq.Features = readGenbankFeatures(scanner)
 		}
 	}
 
@@ -118,9 +151,88 @@ func readGenbank(reader io.Reader) ([]Record, error) {
 	return sequences, nil
 }
 
+// genbankQualifierIndent is the column of feature locations and qualifiers
+const genbankQualifierIndent = 21
+
+// readGenbankFeatures reads the feature table up to the next section keyword.
+func readGenbankFeatures(scanner *lineScanner) []Feature {
+	var features []Feature
+	var feature *Feature
+	var qualifier string
+	openQuote := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !unicode.IsSpace(rune(line[0])) {
+			scanner.Unread()
+			break
+		}
+
+		// a feature key starts at column 5, locations and qualifiers continue at column 21
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		content := strings.TrimSpace(line)
+
+		switch {
+		case indent < genbankQualifierIndent:
+			fields := strings.Fields(content)
+			features = append(features, Feature{Type: fields[0], Qualifiers: make(map[string][]string)})
+			feature = &features[len(features)-1]
+			feature.Location = strings.Join(fields[1:], "")
+			qualifier, openQuote = "", false
+
+		case feature == nil:
+			continue
+
+		case openQuote:
+			// multi-line qualifier value, translations are joined without spaces
+			separator := " "
+			if qualifier == "translation" {
+				separator = ""
+			}
+			values := feature.Qualifiers[qualifier]
+			values[len(values)-1] += separator + unquoteQualifier(strings.TrimSuffix(content, `"`))
+			openQuote = !strings.HasSuffix(content, `"`)
+
+		case strings.HasPrefix(content, "/"):
+			key, value, _ := strings.Cut(content[1:], "=")
+			qualifier = key
+			openQuote = strings.HasPrefix(value, `"`) && (len(value) == 1 || !strings.HasSuffix(value, `"`))
+			value = unquoteQualifier(strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`))
+
+			// qualifiers such as db_xref can be repeated
+			feature.Qualifiers[key] = append(feature.Qualifiers[key], value)
+
+		case qualifier == "":
+			feature.Location += content
+		}
+	}
+
+	return features
+}
+
+// unquoteQualifier turns the doubled quotes of a qualifier value back into single ones
+func unquoteQualifier(value string) string {
+	return strings.ReplaceAll(value, `""`, `"`)
+}
+
 func writeGenbank(writer io.Writer, sequences []Record) error {
 	for _, seq := range sequences {
-		_, err := fmt.Fprintf(writer, "LOCUS       %s\n", seq.ID)
+		topology := "linear"
+		if seq.Circular {
+			topology = "circular"
+		}
+		molecule := "DNA"
+		if strings.ContainsAny(seq.Sequence, "Uu") {
+			molecule = "RNA"
+		}
+
+		// fixed columns of the GenBank release notes, records carry no division or date so
+		// unannotated and the placeholder date are written
+		_, err := fmt.Fprintf(writer, "LOCUS       %-16s %11d bp    %-6s  %-8s UNA 01-JAN-1980\n",
+			seq.ID, len(seq.Sequence), molecule, topology)
 		if err != nil {
 			return err
 		}
@@ -1,6 +1,7 @@
 package bioio
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"reflect"
@@ -154,3 +155,128 @@ func TestReadGenbank(t *testing.T) {
 		})
 	}
 }
+
+var genbankFileCircular = `LOCUS       PLASMID1               12 bp    DNA     circular SYN 01-JAN-1980
+DEFINITION  Test plasmid.
+ACCESSION   PLASMID1
+VERSION     PLASMID1.1
+ORIGIN
+        1 atgaaataat ag
+//`
+
+func TestGenbankTopology(t *testing.T) {
+	records, err := readGenbank(strings.NewReader(genbankFileCircular + "\n" + genbankFileDNA))
+	if err != nil {
+		t.Fatalf("readGenbank() error = %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	if !records[0].Circular {
+		t.Errorf("expected %s to be circular", records[0].ID)
+	}
+	if records[1].Circular || records[2].Circular {
+		t.Errorf("expected linear records to have Circular = false")
+	}
+
+	var buf bytes.Buffer
+	err = writeGenbank(&buf, records[:2])
+	if err != nil {
+		t.Fatalf("writeGenbank() error = %v", err)
+	}
+
+	var locusLines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "LOCUS") {
+			locusLines = append(locusLines, line)
+		}
+	}
+	expectedLines := []string{
+		"LOCUS       PLASMID1                  12 bp    DNA     circular UNA 01-JAN-1980",
+		"LOCUS       TEST123                  120 bp    DNA     linear   UNA 01-JAN-1980",
+	}
+	if !reflect.DeepEqual(locusLines, expectedLines) {
+		t.Errorf("unexpected LOCUS lines %q, expected %q", locusLines, expectedLines)
+	}
+
+	written, err := readGenbank(&buf)
+	if err != nil {
+		t.Fatalf("readGenbank() error = %v", err)
+	}
+	if len(written) != 2 || !written[0].Circular || written[1].Circular || written[0].Sequence != records[0].Sequence {
+		t.Errorf("topology was not preserved by writeGenbank: %+v", written)
+	}
+}
+
+var genbankFileFeatures = `LOCUS       FEAT1                  30 bp    DNA     linear   SYN 01-JAN-1980
+DEFINITION  Test features.
+ACCESSION   FEAT1
+VERSION     FEAT1.1
+SOURCE      synthetic construct
+  ORGANISM  synthetic construct
+            other sequences; artificial sequences.
+FEATURES             Location/Qualifiers
+     source          1..30
+                     /organism="synthetic construct"
+                     /db_xref="taxon:32630"
+     CDS             join(1..6,
+                     10..18)
+                     /locus_tag="FEAT_0001"
+                     /codon_start=1
+                     /note="a ""quoted"" note spanning
+                     two lines"
+                     /translation="MKLV
+                     W"
+                     /db_xref="GeneID:1"
+                     /db_xref="GeneID:2"
+                     /pseudo
+ORIGIN
+        1 atgaaagggc tggtgtggta agggcccaaa
+//`
+
+func TestGenbankFeatures(t *testing.T) {
+	records, err := readGenbank(strings.NewReader(genbankFileFeatures))
+	if err != nil {
+		t.Fatalf("readGenbank() error = %v", err)
+	}
+
+	expected := []Feature{
+		{
+			Type:     "source",
+			Location: "1..30",
+			Qualifiers: map[string][]string{
+				"organism": {"synthetic construct"},
+				"db_xref":  {"taxon:32630"},
+			},
+		},
+		{
+			Type:     "CDS",
+			Location: "join(1..6,10..18)",
+			Qualifiers: map[string][]string{
+				"locus_tag":   {"FEAT_0001"},
+				"codon_start": {"1"},
+				"note":        {`a "quoted" note spanning two lines`},
+				"translation": {"MKLVW"},
+				"db_xref":     {"GeneID:1", "GeneID:2"},
+				"pseudo":      {""},
+			},
+		},
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if !reflect.DeepEqual(records[0].Features, expected) {
+		t.Errorf("unexpected features %+v", records[0].Features)
+	}
+	if records[0].Taxonomy != "other sequences; artificial sequences." {
+		t.Errorf("unexpected taxonomy %q", records[0].Taxonomy)
+	}
+	if value, ok := records[0].Features[1].Qualifier("db_xref"); !ok || value != "GeneID:1" {
+		t.Errorf("Qualifier() expected the first db_xref, got %q, %v", value, ok)
+	}
+	if _, ok := records[0].Features[1].Qualifier("gene"); ok {
+		t.Errorf("Qualifier() expected missing gene qualifier")
+	}
+}
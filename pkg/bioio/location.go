@@ -0,0 +1,124 @@
+package bioio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Span is a part of a feature location. Start and End are 0-based, end-exclusive positions,
+// Complement is set for spans on the reverse strand.
+type Span struct {
+	Start      int
+	End        int
+	Complement bool
+}
+
+// ParseLocation parses an INSDC feature location such as "complement(join(1..10,20..>30))" into spans
+// in the order they are read in, i.e. 5'->3' on the strand of the feature. Partial markers (< and >) are
+// ignored, join and order are treated alike. Remote locations and sites between bases are not supported.
+func ParseLocation(location string) ([]Span, error) {
+	location = strings.Join(strings.Fields(location), "")
+
+	spans, err := parseLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", location, err)
+	}
+
+	return spans, nil
+}
+
+func parseLocation(location string) ([]Span, error) {
+	switch {
+	case strings.HasPrefix(location, "complement(") && strings.HasSuffix(location, ")"):
+		inner, err := parseLocation(location[len("complement(") : len(location)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		// the reverse strand is read from the last span backwards
+		spans := make([]Span, len(inner))
+		for i, span := range inner {
+			span.Complement = !span.Complement
+			spans[len(inner)-1-i] = span
+		}
+		return spans, nil
+
+	case strings.HasPrefix(location, "join(") && strings.HasSuffix(location, ")"):
+		return parseLocationList(location[len("join(") : len(location)-1])
+
+	case strings.HasPrefix(location, "order(") && strings.HasSuffix(location, ")"):
+		return parseLocationList(location[len("order(") : len(location)-1])
+	}
+
+	span, err := parseSpan(location)
+	if err != nil {
+		return nil, err
+	}
+	return []Span{span}, nil
+}
+
+// parseLocationList parses comma separated locations, commas inside nested parentheses are skipped
+func parseLocationList(list string) ([]Span, error) {
+	var spans []Span
+
+	depth, start := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		part, err := parseLocation(list[start:i])
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, part...)
+		start = i + 1
+	}
+
+	return spans, nil
+}
+
+func parseSpan(span string) (Span, error) {
+	if strings.ContainsAny(span, ":^") {
+		return Span{}, fmt.Errorf("unsupported location %q", span)
+	}
+
+	first, last, isRange := strings.Cut(span, "..")
+	if !isRange {
+		last = first
+	}
+
+	start, err := strconv.Atoi(strings.TrimLeft(first, "<>"))
+	if err != nil {
+		return Span{}, fmt.Errorf("invalid position %q", first)
+	}
+	end, err := strconv.Atoi(strings.TrimLeft(last, "<>"))
+	if err != nil {
+		return Span{}, fmt.Errorf("invalid position %q", last)
+	}
+
+	if start < 1 || end < start {
+		return Span{}, fmt.Errorf("invalid range %q", span)
+	}
+
+	return Span{Start: start - 1, End: end}, nil
+}
+
+// Spans parses the location of the feature.
+func (f Feature) Spans() ([]Span, error) {
+	return ParseLocation(f.Location)
+}
@@ -0,0 +1,56 @@
+package bioio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		expected []Span
+		wantErr  bool
+	}{
+		{name: "range", location: "10..20", expected: []Span{{Start: 9, End: 20}}},
+		{name: "single-base", location: "5", expected: []Span{{Start: 4, End: 5}}},
+		{name: "partial", location: "<1..>30", expected: []Span{{Start: 0, End: 30}}},
+		{name: "complement", location: "complement(10..20)", expected: []Span{{Start: 9, End: 20, Complement: true}}},
+		{
+			name:     "join",
+			location: "join(1..10, 20..30)",
+			expected: []Span{{Start: 0, End: 10}, {Start: 19, End: 30}},
+		},
+		{
+			name:     "complement-join",
+			location: "complement(join(1..10,20..30))",
+			expected: []Span{{Start: 19, End: 30, Complement: true}, {Start: 0, End: 10, Complement: true}},
+		},
+		{
+			name:     "join-complements",
+			location: "join(complement(20..30),complement(1..10))",
+			expected: []Span{{Start: 19, End: 30, Complement: true}, {Start: 0, End: 10, Complement: true}},
+		},
+		{
+			name:     "order",
+			location: "order(1..3,complement(5..7))",
+			expected: []Span{{Start: 0, End: 3}, {Start: 4, End: 7, Complement: true}},
+		},
+		{name: "remote", location: "J00194.1:100..202", wantErr: true},
+		{name: "between-bases", location: "123^124", wantErr: true},
+		{name: "reversed-range", location: "20..10", wantErr: true},
+		{name: "not-a-number", location: "a..10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLocation(tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseLocation() got = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -12,12 +12,26 @@ type Record struct {
 	Features    []Feature
 	References  []Reference
 	Sequence    string
+	// Circular is set for circular molecules such as plasmids and organelle genomes.
+	Circular bool
 }
 
 type Feature struct {
-	Type       string
-	Location   string
-	Qualifiers map[string]string
+	Type     string
+	Location string
+	// Qualifiers hold the values of every qualifier in source order, repeated qualifiers such as db_xref
+	// have several values and flags such as /pseudo have a single empty one.
+	Qualifiers map[string][]string
+}
+
+// Qualifier returns the first value of a qualifier.
+func (f Feature) Qualifier(key string) (string, bool) {
+	values := f.Qualifiers[key]
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
 }
 
 type Reference struct {
@@ -0,0 +1,202 @@
+package entrez
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils/"
+	DefaultTool    = "ribosome"
+
+	// NCBI allows 3 requests per second without an API key and 10 with one.
+	rateWithoutKey = 3
+	rateWithKey    = 10
+
+	defaultMaxRetries = 3
+	defaultRetryDelay = 500 * time.Millisecond
+
+	// maxGETIDs is the longest id list sent in the query string, NCBI recommends POST for longer lists.
+	maxGETIDs = 200
+)
+
+var ErrEmptyIDList = errors.New("at least one id must be provided")
+
+// Client calls the NCBI Entrez E-utilities. The zero value is not usable, create clients with NewClient.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	Tool       string
+	Email      string
+	HTTPClient *http.Client
+
+	// RateLimit is the maximum number of requests per second.
+	RateLimit int
+	// MaxRetries is the number of retries after the first attempt, negative values are treated as 0.
+	MaxRetries int
+	RetryDelay time.Duration
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+// APIError is returned when E-utilities answer with an error status or an error message in the body.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("entrez: status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("entrez: %s", e.Message)
+}
+
+func NewClient(apiKey string) *Client {
+	rate := rateWithoutKey
+	if apiKey != "" {
+		rate = rateWithKey
+	}
+
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		APIKey:     apiKey,
+		Tool:       DefaultTool,
+		HTTPClient: http.DefaultClient,
+		RateLimit:  rate,
+		MaxRetries: defaultMaxRetries,
+		RetryDelay: defaultRetryDelay,
+	}
+}
+
+// wait blocks until the next request is allowed by the rate limit.
+func (c *Client) wait(ctx context.Context) error {
+	if c.RateLimit <= 0 {
+		return nil
+	}
+	interval := time.Second / time.Duration(c.RateLimit)
+
+	c.mu.Lock()
+	next := c.lastRequest.Add(interval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	c.lastRequest = next
+	c.mu.Unlock()
+
+	return sleep(ctx, next.Sub(now))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *Client) endpoint(utility string) string {
+	return strings.TrimRight(c.BaseURL, "/") + "/" + utility + ".fcgi"
+}
+
+func (c *Client) withCredentials(params url.Values) url.Values {
+	if c.APIKey != "" {
+		params.Set("api_key", c.APIKey)
+	}
+	if c.Tool != "" {
+		params.Set("tool", c.Tool)
+	}
+	if c.Email != "" {
+		params.Set("email", c.Email)
+	}
+
+	return params
+}
+
+// newRequest sends the parameters in the query string, or as a POST form for id lists longer than maxGETIDs
+func (c *Client) newRequest(ctx context.Context, utility, query string, post bool) (*http.Request, error) {
+	if !post {
+		return http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(utility)+"?"+query, nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(utility), strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req, nil
+}
+
+// call performs a rate-limited request, retrying on transport errors, 429 and 5xx responses.
+// The caller is responsible for closing the returned body.
+func (c *Client) call(ctx context.Context, utility string, params url.Values) (io.ReadCloser, error) {
+	query := c.withCredentials(params).Encode()
+	post := strings.Count(params.Get("id"), ",")+1 > maxGETIDs
+
+	attempts := c.MaxRetries + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			// exponential backoff: delay, 2*delay, 4*delay...
+			err := sleep(ctx, c.RetryDelay<<(attempt-1))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err := c.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := c.newRequest(ctx, utility, query, post)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return resp.Body, nil
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+
+		lastErr = &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return nil, lastErr
+		}
+	}
+
+	return nil, fmt.Errorf("entrez: %s failed after %d attempts: %w", utility, attempts, lastErr)
+}
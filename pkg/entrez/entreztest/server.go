@@ -0,0 +1,183 @@
+// Package entreztest provides an offline stand-in for the NCBI E-utilities used to test Entrez clients.
+package entreztest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+type Entry struct {
+	UID       string
+	Accession string
+	Title     string
+	Organism  string
+	TaxID     int
+	Length    int
+	// GenBank is the flat file returned by efetch.
+	GenBank string
+}
+
+type Request struct {
+	Method  string
+	Utility string
+	Params  map[string]string
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	entries  []Entry
+	failures []int
+	requests []Request
+}
+
+func NewServer(entries ...Entry) *Server {
+	s := &Server{entries: entries}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// FailNext makes the next len(statuses) requests fail with the given HTTP statuses.
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Requests returns all requests received so far, including failed ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	utility := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".fcgi")
+
+	// parameters come in the query string or, for long id lists, in a POST form
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := make(map[string]string)
+	for k, v := range r.Form {
+		params[k] = strings.Join(v, ",")
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Utility: utility, Params: params})
+	var failure int
+	if len(s.failures) > 0 {
+		failure = s.failures[0]
+		s.failures = s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if failure != 0 {
+		http.Error(w, http.StatusText(failure), failure)
+		return
+	}
+
+	switch utility {
+	case "esearch":
+		s.esearch(w, params)
+	case "esummary":
+		s.esummary(w, params)
+	case "efetch":
+		s.efetch(w, params)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) lookup(id string) (Entry, bool) {
+	for _, e := range s.entries {
+		if e.UID == id || e.Accession == id || strings.SplitN(e.Accession, ".", 2)[0] == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (s *Server) esearch(w http.ResponseWriter, params map[string]string) {
+	term := strings.ToLower(params["term"])
+	if term == "" {
+		writeJSON(w, map[string]any{"esearchresult": map[string]any{"ERROR": "Empty term and query_key - nothing todo"}})
+		return
+	}
+
+	ids := make([]string, 0)
+	for _, e := range s.entries {
+		if strings.Contains(strings.ToLower(e.Accession+" "+e.Title+" "+e.Organism), term) {
+			ids = append(ids, e.UID)
+		}
+	}
+
+	writeJSON(w, map[string]any{
+		"esearchresult": map[string]any{
+			"count":    fmt.Sprint(len(ids)),
+			"retmax":   fmt.Sprint(len(ids)),
+			"retstart": "0",
+			"idlist":   ids,
+		},
+	})
+}
+
+func (s *Server) esummary(w http.ResponseWriter, params map[string]string) {
+	result := map[string]any{}
+	uids := make([]string, 0)
+
+	for _, id := range strings.Split(params["id"], ",") {
+		e, ok := s.lookup(id)
+		if !ok {
+			uids = append(uids, id)
+			result[id] = map[string]any{"uid": id, "error": "cannot get document summary"}
+			continue
+		}
+
+		uids = append(uids, e.UID)
+		result[e.UID] = map[string]any{
+			"uid":              e.UID,
+			"caption":          strings.SplitN(e.Accession, ".", 2)[0],
+			"title":            e.Title,
+			"accessionversion": e.Accession,
+			"organism":         e.Organism,
+			"taxid":            e.TaxID,
+			"slen":             e.Length,
+		}
+	}
+	result["uids"] = uids
+
+	writeJSON(w, map[string]any{"result": result})
+}
+
+func (s *Server) efetch(w http.ResponseWriter, params map[string]string) {
+	if params["rettype"] != "gb" {
+		http.Error(w, "unsupported rettype", http.StatusBadRequest)
+		return
+	}
+
+	var sb strings.Builder
+	for _, id := range strings.Split(params["id"], ",") {
+		e, ok := s.lookup(id)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Failed to retrieve sequence %s", id), http.StatusBadRequest)
+			return
+		}
+		sb.WriteString(strings.TrimRight(e.GenBank, "\n"))
+		sb.WriteString("\n\n")
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, sb.String())
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,187 @@
+package entrez
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+)
+
+const NucleotideDB = "nuccore"
+
+type SearchResult struct {
+	Count    int
+	RetStart int
+	RetMax   int
+	IDs      []string
+}
+
+type Summary struct {
+	UID              string
+	Caption          string
+	Title            string
+	AccessionVersion string
+	Organism         string
+	TaxID            int
+	Length           int
+}
+
+// ESearch returns the UIDs matching term in the given database.
+func (c *Client) ESearch(ctx context.Context, db, term string, retMax int) (*SearchResult, error) {
+	params := url.Values{}
+	params.Set("db", db)
+	params.Set("term", term)
+	params.Set("retmode", "json")
+	if retMax > 0 {
+		params.Set("retmax", strconv.Itoa(retMax))
+	}
+
+	body, err := c.call(ctx, "esearch", params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp struct {
+		Error  string `json:"error"`
+		Result struct {
+			Count    string   `json:"count"`
+			RetMax   string   `json:"retmax"`
+			RetStart string   `json:"retstart"`
+			IDList   []string `json:"idlist"`
+			Error    string   `json:"ERROR"`
+		} `json:"esearchresult"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("entrez: decoding esearch response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, &APIError{Message: resp.Error}
+	}
+	if resp.Result.Error != "" {
+		return nil, &APIError{Message: resp.Result.Error}
+	}
+
+	result := &SearchResult{IDs: resp.Result.IDList}
+	for _, field := range []struct {
+		raw string
+		dst *int
+	}{
+		{resp.Result.Count, &result.Count},
+		{resp.Result.RetMax, &result.RetMax},
+		{resp.Result.RetStart, &result.RetStart},
+	} {
+		if field.raw == "" {
+			continue
+		}
+		*field.dst, err = strconv.Atoi(field.raw)
+		if err != nil {
+			return nil, fmt.Errorf("entrez: invalid esearch number %q: %w", field.raw, err)
+		}
+	}
+
+	return result, nil
+}
+
+// ESummary returns document summaries for the given UIDs or accessions, in the order they were returned by NCBI.
+func (c *Client) ESummary(ctx context.Context, db string, ids ...string) ([]Summary, error) {
+	if len(ids) == 0 {
+		return nil, ErrEmptyIDList
+	}
+
+	params := url.Values{}
+	params.Set("db", db)
+	params.Set("id", strings.Join(ids, ","))
+	params.Set("retmode", "json")
+
+	body, err := c.call(ctx, "esummary", params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp struct {
+		Error  string                     `json:"error"`
+		Result map[string]json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("entrez: decoding esummary response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, &APIError{Message: resp.Error}
+	}
+
+	var uids []string
+	if raw, ok := resp.Result["uids"]; ok {
+		if err := json.Unmarshal(raw, &uids); err != nil {
+			return nil, fmt.Errorf("entrez: decoding esummary uids: %w", err)
+		}
+	}
+
+	summaries := make([]Summary, 0, len(uids))
+	for _, uid := range uids {
+		var doc struct {
+			UID              string `json:"uid"`
+			Caption          string `json:"caption"`
+			Title            string `json:"title"`
+			AccessionVersion string `json:"accessionversion"`
+			Organism         string `json:"organism"`
+			TaxID            int    `json:"taxid"`
+			Length           int    `json:"slen"`
+			Error            string `json:"error"`
+		}
+		if err := json.Unmarshal(resp.Result[uid], &doc); err != nil {
+			return nil, fmt.Errorf("entrez: decoding esummary document %s: %w", uid, err)
+		}
+		if doc.Error != "" {
+			return nil, &APIError{Message: fmt.Sprintf("%s: %s", uid, doc.Error)}
+		}
+
+		summaries = append(summaries, Summary{
+			UID:              doc.UID,
+			Caption:          doc.Caption,
+			Title:            doc.Title,
+			AccessionVersion: doc.AccessionVersion,
+			Organism:         doc.Organism,
+			TaxID:            doc.TaxID,
+			Length:           doc.Length,
+		})
+	}
+
+	return summaries, nil
+}
+
+// EFetch downloads GenBank flat files for the given UIDs or accessions and parses them into records.
+func (c *Client) EFetch(ctx context.Context, db string, ids ...string) ([]bioio.Record, error) {
+	if len(ids) == 0 {
+		return nil, ErrEmptyIDList
+	}
+
+	params := url.Values{}
+	params.Set("db", db)
+	params.Set("id", strings.Join(ids, ","))
+	params.Set("rettype", "gb")
+	params.Set("retmode", "text")
+
+	body, err := c.call(ctx, "efetch", params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	records, err := bioio.Read(body, bioio.Genbank)
+	if err != nil {
+		return nil, fmt.Errorf("entrez: parsing efetch response: %w", err)
+	}
+
+	return records, nil
+}
+
+// FetchNucleotide is a shortcut for EFetch against the nucleotide database.
+func (c *Client) FetchNucleotide(ctx context.Context, accessions ...string) ([]bioio.Record, error) {
+	return c.EFetch(ctx, NucleotideDB, accessions...)
+}
@@ -0,0 +1,328 @@
+package entrez
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+	"github.com/dissipative/ribosome/pkg/entrez/entreztest"
+)
+
+var genbankMito = `LOCUS       NC_000001              24 bp    DNA     circular MAM 01-JAN-2020
+DEFINITION  Test mitochondrion, complete genome.
+ACCESSION   NC_000001
+VERSION     NC_000001.2
+KEYWORDS    RefSeq.
+SOURCE      mitochondrion Testus testus
+  ORGANISM  Testus testus
+            Eukaryota; Metazoa.
+FEATURES             Location/Qualifiers
+ORIGIN
+        1 atgcgaattc agatggcact gaaa
+//`
+
+var genbankPlasmid = `LOCUS       NC_000002              12 bp    DNA     circular BCT 01-JAN-2020
+DEFINITION  Test plasmid.
+ACCESSION   NC_000002
+VERSION     NC_000002.1
+KEYWORDS    RefSeq.
+SOURCE      Bacterium testus
+  ORGANISM  Bacterium testus
+            Bacteria.
+FEATURES             Location/Qualifiers
+ORIGIN
+        1 atgaaataat ag
+//`
+
+func newTestClient(t *testing.T, apiKey string) (*Client, *entreztest.Server) {
+	t.Helper()
+
+	server := entreztest.NewServer(
+		entreztest.Entry{
+			UID:       "1001",
+			Accession: "NC_000001.2",
+			Title:     "Test mitochondrion, complete genome",
+			Organism:  "Testus testus",
+			TaxID:     42,
+			Length:    24,
+			GenBank:   genbankMito,
+		},
+		entreztest.Entry{
+			UID:       "1002",
+			Accession: "NC_000002.1",
+			Title:     "Test plasmid",
+			Organism:  "Bacterium testus",
+			TaxID:     43,
+			Length:    12,
+			GenBank:   genbankPlasmid,
+		},
+	)
+	t.Cleanup(server.Close)
+
+	client := NewClient(apiKey)
+	client.BaseURL = server.URL
+	client.Email = "lab@example.org"
+	client.RateLimit = 1000
+	client.RetryDelay = time.Millisecond
+
+	return client, server
+}
+
+func TestClient_EFetch(t *testing.T) {
+	client, server := newTestClient(t, "secret")
+
+	got, err := client.FetchNucleotide(context.Background(), "NC_000001", "NC_000002.1")
+	if err != nil {
+		t.Fatalf("EFetch() error = %v", err)
+	}
+
+	expected := []bioio.Record{
+		{
+			ID:          "NC_000001",
+			Version:     2,
+			Organism:    "Testus testus",
+			Taxonomy:    "Eukaryota; Metazoa.",
+			Description: "Test mitochondrion, complete genome.",
+			Sequence:    "atgcgaattcagatggcactgaaa",
+			Circular:    true,
+		},
+		{
+			ID:          "NC_000002",
+			Version:     1,
+			Organism:    "Bacterium testus",
+			Taxonomy:    "Bacteria.",
+			Description: "Test plasmid.",
+			Sequence:    "atgaaataatag",
+			Circular:    true,
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EFetch() got = %+v, expected %+v", got, expected)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	for param, value := range map[string]string{
+		"db":      "nuccore",
+		"id":      "NC_000001,NC_000002.1",
+		"rettype": "gb",
+		"api_key": "secret",
+		"tool":    DefaultTool,
+		"email":   "lab@example.org",
+	} {
+		if requests[0].Params[param] != value {
+			t.Errorf("expected %s=%q, got %q", param, value, requests[0].Params[param])
+		}
+	}
+}
+
+func TestClient_ESearch(t *testing.T) {
+	client, _ := newTestClient(t, "")
+
+	tests := []struct {
+		name     string
+		term     string
+		expected *SearchResult
+		wantErr  bool
+	}{
+		{
+			name:     "single-hit",
+			term:     "plasmid",
+			expected: &SearchResult{Count: 1, RetMax: 1, IDs: []string{"1002"}},
+		},
+		{
+			name:     "multiple-hits",
+			term:     "testus",
+			expected: &SearchResult{Count: 2, RetMax: 2, IDs: []string{"1001", "1002"}},
+		},
+		{
+			name:     "no-hits",
+			term:     "chloroplast",
+			expected: &SearchResult{IDs: []string{}},
+		},
+		{
+			name:    "error-in-body",
+			term:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.ESearch(context.Background(), NucleotideDB, tt.term, 20)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ESearch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ESearch() got = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClient_ESummary(t *testing.T) {
+	client, _ := newTestClient(t, "")
+
+	got, err := client.ESummary(context.Background(), NucleotideDB, "1001")
+	if err != nil {
+		t.Fatalf("ESummary() error = %v", err)
+	}
+
+	expected := []Summary{{
+		UID:              "1001",
+		Caption:          "NC_000001",
+		Title:            "Test mitochondrion, complete genome",
+		AccessionVersion: "NC_000001.2",
+		Organism:         "Testus testus",
+		TaxID:            42,
+		Length:           24,
+	}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ESummary() got = %+v, expected %+v", got, expected)
+	}
+
+	_, err = client.ESummary(context.Background(), NucleotideDB, "404")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("ESummary() expected APIError for unknown id, got %v", err)
+	}
+
+	_, err = client.ESummary(context.Background(), NucleotideDB)
+	if !errors.Is(err, ErrEmptyIDList) {
+		t.Errorf("ESummary() expected ErrEmptyIDList, got %v", err)
+	}
+}
+
+func TestClient_ESummary_Post(t *testing.T) {
+	client, server := newTestClient(t, "")
+
+	ids := make([]string, 0, maxGETIDs+1)
+	for len(ids) < maxGETIDs {
+		ids = append(ids, "1001")
+	}
+
+	if _, err := client.ESummary(context.Background(), NucleotideDB, ids...); err != nil {
+		t.Fatalf("ESummary() error = %v", err)
+	}
+	got, err := client.ESummary(context.Background(), NucleotideDB, append(ids, "1002")...)
+	if err != nil {
+		t.Fatalf("ESummary() error = %v", err)
+	}
+	if len(got) != maxGETIDs+1 || got[maxGETIDs].UID != "1002" {
+		t.Errorf("expected %d summaries ending with 1002, got %d", maxGETIDs+1, len(got))
+	}
+
+	requests := server.Requests()
+	if requests[0].Method != http.MethodGet || requests[1].Method != http.MethodPost {
+		t.Errorf("expected GET for %d ids and POST above, got %s and %s", maxGETIDs, requests[0].Method, requests[1].Method)
+	}
+	if requests[1].Params["email"] != "lab@example.org" {
+		t.Errorf("POST request lost the credentials %v", requests[1].Params)
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     []int
+		maxRetries   int
+		wantErr      bool
+		wantRequests int
+	}{
+		{
+			name:         "recover-after-server-errors",
+			failures:     []int{http.StatusInternalServerError, http.StatusTooManyRequests},
+			maxRetries:   3,
+			wantRequests: 3,
+		},
+		{
+			name:         "give-up-after-max-retries",
+			failures:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			maxRetries:   2,
+			wantErr:      true,
+			wantRequests: 3,
+		},
+		{
+			name:         "negative-max-retries",
+			failures:     []int{http.StatusBadGateway},
+			maxRetries:   -1,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "no-retry-on-client-error",
+			failures:     []int{http.StatusBadRequest},
+			maxRetries:   3,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t, "")
+			client.MaxRetries = tt.maxRetries
+			server.FailNext(tt.failures...)
+
+			_, err := client.FetchNucleotide(context.Background(), "NC_000002")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EFetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var apiErr *APIError
+			if tt.wantErr && !errors.As(err, &apiErr) {
+				t.Errorf("expected APIError, got %v", err)
+			}
+			if got := len(server.Requests()); got != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, got)
+			}
+		})
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	client, _ := newTestClient(t, "")
+	client.RateLimit = 20
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.ESummary(context.Background(), NucleotideDB, "1001")
+		if err != nil {
+			t.Fatalf("ESummary() error = %v", err)
+		}
+	}
+
+	// 4 requests at 20 req/s need at least 3 intervals of 50ms
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("requests were not rate limited: 4 requests took %v", elapsed)
+	}
+}
+
+func TestNewClient_RateLimit(t *testing.T) {
+	if got := NewClient("").RateLimit; got != 3 {
+		t.Errorf("expected 3 requests per second without API key, got %d", got)
+	}
+	if got := NewClient("key").RateLimit; got != 10 {
+		t.Errorf("expected 10 requests per second with API key, got %d", got)
+	}
+}
+
+func TestClient_ContextCancel(t *testing.T) {
+	client, server := newTestClient(t, "")
+	client.RetryDelay = time.Hour
+	server.FailNext(http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.FetchNucleotide(ctx, "NC_000001")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package kmer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The count file starts with a header of the magic bytes, the format version, k and the number of k-mers
+// (uint64, little endian). Entries follow in increasing k-mer order, each as the uvarint difference to
+// the previous k-mer and the uvarint count, so dense k-mer sets take a few bytes per k-mer.
+var countFileMagic = [4]byte{'R', 'B', 'K', 'C'}
+
+const countFileVersion = 1
+
+var ErrInvalidCountFile = errors.New("invalid k-mer count file")
+
+// WriteTo writes the counts in the binary count file format.
+func (c *Counts) WriteTo(writer io.Writer) (int64, error) {
+	buffered := bufio.NewWriter(writer)
+
+	header := make([]byte, 0, 14)
+	header = append(header, countFileMagic[:]...)
+	header = append(header, countFileVersion, byte(c.K))
+	header = binary.LittleEndian.AppendUint64(header, uint64(len(c.counts)))
+
+	written, err := buffered.Write(header)
+	total := int64(written)
+	if err != nil {
+		return total, err
+	}
+
+	var previous uint64
+	entry := make([]byte, 0, 2*binary.MaxVarintLen64)
+	for _, kmer := range c.sortedKMers() {
+		entry = binary.AppendUvarint(entry[:0], kmer-previous)
+		entry = binary.AppendUvarint(entry, c.counts[kmer])
+		previous = kmer
+
+		written, err = buffered.Write(entry)
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, buffered.Flush()
+}
+
+// ReadCounts reads counts written by Counts.WriteTo.
+func ReadCounts(reader io.Reader) (*Counts, error) {
+	buffered := bufio.NewReader(reader)
+
+	var header [14]byte
+	if _, err := io.ReadFull(buffered, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCountFile, err)
+	}
+	if [4]byte(header[:4]) != countFileMagic {
+		return nil, fmt.Errorf("%w: unknown format", ErrInvalidCountFile)
+	}
+	if header[4] != countFileVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCountFile, header[4])
+	}
+
+	counts, err := NewCounts(int(header[5]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCountFile, err)
+	}
+
+	n := binary.LittleEndian.Uint64(header[6:])
+	var kmer uint64
+	for i := uint64(0); i < n; i++ {
+		delta, err := binary.ReadUvarint(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%w: k-mer %d: %v", ErrInvalidCountFile, i, err)
+		}
+		count, err := binary.ReadUvarint(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%w: k-mer %d: %v", ErrInvalidCountFile, i, err)
+		}
+
+		kmer += delta
+		counts.counts[kmer] = count
+	}
+
+	return counts, nil
+}
+
+// A sketch file holds sketches one after another, each with a header of the magic bytes, the format version,
+// k, the sketch size (uint32), the number of k-mers (uint64), the number of hashes (uint32) and the length
+// of the name (uint16, all little endian), followed by the name and the uvarint differences of the sorted hashes.
+var sketchFileMagic = [4]byte{'R', 'B', 'M', 'S'}
+
+const (
+	sketchFileVersion    = 1
+	sketchFileHeaderSize = 4 + 1 + 1 + 4 + 8 + 4 + 2
+)
+
+var ErrInvalidSketchFile = errors.New("invalid sketch file")
+
+// WriteTo writes the sketch in the binary sketch file format.
+func (s *Sketch) WriteTo(writer io.Writer) (int64, error) {
+	if len(s.Name) > math.MaxUint16 {
+		return 0, fmt.Errorf("sketch name is longer than %d bytes", math.MaxUint16)
+	}
+
+	data := make([]byte, 0, sketchFileHeaderSize+len(s.Name)+len(s.Hashes)*binary.MaxVarintLen64)
+	data = append(data, sketchFileMagic[:]...)
+	data = append(data, sketchFileVersion, byte(s.K))
+	data = binary.LittleEndian.AppendUint32(data, uint32(s.Size))
+	data = binary.LittleEndian.AppendUint64(data, s.Length)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(s.Hashes)))
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(s.Name)))
+	data = append(data, s.Name...)
+
+	var previous uint64
+	for _, hash := range s.Hashes {
+		data = binary.AppendUvarint(data, hash-previous)
+		previous = hash
+	}
+
+	written, err := writer.Write(data)
+	return int64(written), err
+}
+
+// WriteSketches writes sketches to a single file.
+func WriteSketches(writer io.Writer, sketches []*Sketch) error {
+	buffered := bufio.NewWriter(writer)
+	for _, sketch := range sketches {
+		if _, err := sketch.WriteTo(buffered); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
+
+// ReadSketches reads all sketches of a file written by WriteSketches or Sketch.WriteTo.
+func ReadSketches(reader io.Reader) ([]*Sketch, error) {
+	buffered := bufio.NewReader(reader)
+
+	var sketches []*Sketch
+	for {
+		sketch, err := readSketch(buffered)
+		if err == io.EOF {
+			return sketches, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: sketch %d: %v", ErrInvalidSketchFile, len(sketches)+1, err)
+		}
+
+		sketches = append(sketches, sketch)
+	}
+}
+
+// readSketch returns io.EOF only if the reader ends before the first byte of a sketch
+func readSketch(reader *bufio.Reader) (*Sketch, error) {
+	var header [sketchFileHeaderSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, err
+	}
+	if [4]byte(header[:4]) != sketchFileMagic {
+		return nil, errors.New("unknown format")
+	}
+	if header[4] != sketchFileVersion {
+		return nil, fmt.Errorf("unsupported version %d", header[4])
+	}
+
+	name := make([]byte, binary.LittleEndian.Uint16(header[22:]))
+	if _, err := io.ReadFull(reader, name); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	sketch, err := NewSketch(string(name), int(header[5]), int(binary.LittleEndian.Uint32(header[6:])))
+	if err != nil {
+		return nil, err
+	}
+	sketch.Length = binary.LittleEndian.Uint64(header[10:])
+
+	n := binary.LittleEndian.Uint32(header[18:])
+	if int(n) > sketch.Size {
+		return nil, fmt.Errorf("%d hashes exceed the sketch size %d", n, sketch.Size)
+	}
+
+	sketch.Hashes = make([]uint64, n)
+	var hash uint64
+	for i := range sketch.Hashes {
+		delta, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		hash += delta
+		sketch.Hashes[i] = hash
+	}
+
+	return sketch, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,285 @@
+// Package kmer counts canonical k-mers of DNA sequences, builds k-mer frequency spectra and MinHash sketches.
+package kmer
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+	"github.com/dissipative/ribosome/pkg/sequence"
+	"github.com/dissipative/ribosome/pkg/sets"
+)
+
+// MaxK is the longest k-mer, k-mers are encoded in 2*k bits of a uint64.
+const MaxK = 31
+
+var (
+	ErrInvalidK  = fmt.Errorf("k must be between 1 and %d", MaxK)
+	ErrKMismatch = errors.New("k-mer counts have different k")
+)
+
+// Counts maps canonical k-mers, the smaller encoding of a k-mer and its reverse complement, to their number of
+// occurrences on both strands. K-mers are encoded with 2 bits per base as by sequence.PackedSequence.KMer.
+type Counts struct {
+	K      int
+	counts map[uint64]uint64
+}
+
+func NewCounts(k int) (*Counts, error) {
+	if k < 1 || k > MaxK {
+		return nil, ErrInvalidK
+	}
+
+	return &Counts{K: k, counts: make(map[uint64]uint64)}, nil
+}
+
+// Count returns the canonical k-mer counts of seq.
+func Count(seq sequence.DNASequence, k int) (*Counts, error) {
+	counts, err := NewCounts(k)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = counts.Add(seq); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// Add counts the canonical k-mers of seq, k-mers with ambiguous bases or gaps are skipped.
+// It fails on letters that are not IUPAC nucleotides.
+func (c *Counts) Add(seq sequence.DNASequence) error {
+	return forEachCanonical(seq, c.K, func(kmer uint64) {
+		c.counts[kmer]++
+	})
+}
+
+// forEachCanonical calls fn with the canonical encoding of every k-mer of seq without ambiguous bases
+func forEachCanonical(seq sequence.DNASequence, k int, fn func(kmer uint64)) error {
+	packed, err := sequence.PackDNA(seq)
+	if err != nil {
+		return err
+	}
+
+	// the reverse complement rolls along with consecutive k-mers and is recomputed after ambiguous bases
+	shift := uint(2 * (k - 1))
+	var reverse uint64
+	previous := -2
+	return packed.ForEachKMer(k, func(pos int, kmer uint64) {
+		if pos == previous+1 {
+			reverse = reverse>>2 | (3-kmer&0x3)<<shift
+		} else {
+			reverse = ReverseComplement(kmer, k)
+		}
+		previous = pos
+
+		fn(smaller(kmer, reverse))
+	})
+}
+
+func smaller(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ReverseComplement returns the reverse complement of an encoded k-mer.
+func ReverseComplement(kmer uint64, k int) uint64 {
+	var reverse uint64
+	for i := 0; i < k; i++ {
+		reverse = reverse<<2 | (3 - kmer&0x3)
+		kmer >>= 2
+	}
+	return reverse
+}
+
+// Canonical returns the smaller of an encoded k-mer and its reverse complement.
+func Canonical(kmer uint64, k int) uint64 {
+	return smaller(kmer, ReverseComplement(kmer, k))
+}
+
+// Get returns the count of a k-mer given on either strand, 0 for k-mers of another length or with ambiguous bases.
+func (c *Counts) Get(kmer sequence.DNASequence) uint64 {
+	if len(kmer) != c.K {
+		return 0
+	}
+
+	packed, err := sequence.PackDNA(kmer)
+	if err != nil {
+		return 0
+	}
+	encoded, ok := packed.KMer(0, c.K)
+	if !ok {
+		return 0
+	}
+
+	return c.counts[Canonical(encoded, c.K)]
+}
+
+// Distinct returns the number of distinct canonical k-mers.
+func (c *Counts) Distinct() int {
+	return len(c.counts)
+}
+
+// Total returns the number of counted k-mers.
+func (c *Counts) Total() uint64 {
+	var total uint64
+	for _, count := range c.counts {
+		total += count
+	}
+	return total
+}
+
+// Merge adds the counts of other, e.g. of another record of the same genome.
+func (c *Counts) Merge(other *Counts) error {
+	if c.K != other.K {
+		return fmt.Errorf("%w: %d and %d", ErrKMismatch, c.K, other.K)
+	}
+
+	for kmer, count := range other.counts {
+		c.counts[kmer] += count
+	}
+	return nil
+}
+
+// ForEach calls fn with every canonical k-mer and its count in increasing k-mer order.
+func (c *Counts) ForEach(fn func(kmer, count uint64)) {
+	for _, kmer := range c.sortedKMers() {
+		fn(kmer, c.counts[kmer])
+	}
+}
+
+func (c *Counts) sortedKMers() []uint64 {
+	kmers := make([]uint64, 0, len(c.counts))
+	for kmer := range c.counts {
+		kmers = append(kmers, kmer)
+	}
+	sort.Slice(kmers, func(i, j int) bool { return kmers[i] < kmers[j] })
+
+	return kmers
+}
+
+// Spectrum maps a multiplicity to the number of distinct k-mers seen that many times. Peaks of the spectrum
+// of a genome estimate its coverage, k-mers seen once are mostly sequencing errors or contamination.
+type Spectrum map[uint64]uint64
+
+func (c *Counts) Spectrum() Spectrum {
+	spectrum := make(Spectrum)
+	for _, count := range c.counts {
+		spectrum[count]++
+	}
+	return spectrum
+}
+
+// Multiplicities returns the multiplicities of the spectrum in increasing order.
+func (s Spectrum) Multiplicities() []uint64 {
+	multiplicities := make([]uint64, 0, len(s))
+	for multiplicity := range s {
+		multiplicities = append(multiplicities, multiplicity)
+	}
+	sort.Slice(multiplicities, func(i, j int) bool { return multiplicities[i] < multiplicities[j] })
+
+	return multiplicities
+}
+
+// CountRecords counts the k-mers of every record of the set concurrently and returns the counts by record ID,
+// records sharing an ID are merged. Records of RNA sets are read as their DNA copy.
+func CountRecords(set *sets.Set, k int) (map[string]*Counts, error) {
+	if k < 1 || k > MaxK {
+		return nil, ErrInvalidK
+	}
+
+	records := set.Records()
+	counted := make([]*Counts, len(records))
+
+	err := forEachRecord(set, func(i int, seq sequence.DNASequence) error {
+		var err error
+		counted[i], err = Count(seq, k)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byRecord := make(map[string]*Counts, len(records))
+	for i, record := range records {
+		if counts, ok := byRecord[record.ID]; ok {
+			_ = counts.Merge(counted[i])
+			continue
+		}
+		byRecord[record.ID] = counted[i]
+	}
+
+	return byRecord, nil
+}
+
+// CountSet returns the merged k-mer counts of all records of the set.
+func CountSet(set *sets.Set, k int) (*Counts, error) {
+	byRecord, err := CountRecords(set, k)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, _ := NewCounts(k)
+	for _, counts := range byRecord {
+		_ = merged.Merge(counts)
+	}
+
+	return merged, nil
+}
+
+// forEachRecord calls fn with the index and the DNA of every record of the set on GOMAXPROCS workers,
+// so only as many records are decoded at once. It returns the error of the first failed record.
+func forEachRecord(set *sets.Set, fn func(i int, seq sequence.DNASequence) error) error {
+	records := set.Records()
+	errs := make([]error, len(records))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				seq, err := recordDNA(records[i], set.MolType())
+				if err == nil {
+					err = fn(i, seq)
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("record %s: %w", records[i].ID, err)
+				}
+			}
+		}()
+	}
+
+	for i := range records {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func recordDNA(record bioio.Record, molType int) (sequence.DNASequence, error) {
+	if molType == sets.RNA {
+		rna, err := sequence.NewRNASequence(record.Sequence)
+		if err != nil {
+			return "", err
+		}
+		return rna.BackTranscribe(), nil
+	}
+
+	return sequence.NewDNASequence(record.Sequence)
+}
@@ -0,0 +1,172 @@
+package kmer
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+	"github.com/dissipative/ribosome/pkg/sequence"
+	"github.com/dissipative/ribosome/pkg/sets"
+)
+
+func randomDNA(length int, seed int64) sequence.DNASequence {
+	random := rand.New(rand.NewSource(seed))
+	seq := make([]byte, length)
+	for i := range seq {
+		seq[i] = "ACGT"[random.Intn(4)]
+	}
+	return sequence.DNASequence(seq)
+}
+
+func TestCount(t *testing.T) {
+	// ACG and its reverse complement CGT are the same canonical k-mer, as are GTA and TAC
+	counts, err := Count("ACGTnCGTAC", 3)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+
+	expected := map[sequence.DNASequence]uint64{"ACG": 3, "CGT": 3, "GTA": 2, "TAC": 2, "CGC": 0}
+	for kmer, count := range expected {
+		if got := counts.Get(kmer); got != count {
+			t.Errorf("Get(%s) = %d, expected %d", kmer, got, count)
+		}
+	}
+	if counts.Distinct() != 2 || counts.Total() != 5 {
+		t.Errorf("Distinct() = %d, Total() = %d, expected 2 and 5", counts.Distinct(), counts.Total())
+	}
+
+	if _, err = Count("ACGT", 32); !errors.Is(err, ErrInvalidK) {
+		t.Errorf("expected ErrInvalidK, got %v", err)
+	}
+	if _, err = Count("ACGT*", 2); err == nil {
+		t.Errorf("expected error for an invalid letter")
+	}
+}
+
+func TestCount_BothStrands(t *testing.T) {
+	seq := randomDNA(5_000, 1)
+
+	for _, k := range []int{1, 4, 21, MaxK} {
+		forward, _ := Count(seq, k)
+		reverse, _ := Count(seq.ReverseComplement(), k)
+
+		if !reflect.DeepEqual(forward.counts, reverse.counts) {
+			t.Errorf("k=%d: counts of the two strands differ", k)
+		}
+
+		// the rolling encoding matches the packed sequence encoding
+		packed, _ := sequence.PackDNA(seq)
+		kmer, _ := packed.KMer(100, k)
+		decoded, _ := sequence.DecodeKMer(kmer, k)
+		if forward.Get(decoded) == 0 || Canonical(kmer, k) != Canonical(ReverseComplement(kmer, k), k) {
+			t.Errorf("k=%d: k-mer %s at 100 not found", k, decoded)
+		}
+	}
+}
+
+func TestSpectrum(t *testing.T) {
+	counts, _ := Count("AAAAAGGG", 3)
+	// AAA three times, AAG, AGG and GGG once
+	expected := Spectrum{3: 1, 1: 3}
+
+	spectrum := counts.Spectrum()
+	if !reflect.DeepEqual(spectrum, expected) {
+		t.Errorf("Spectrum() = %v, expected %v", spectrum, expected)
+	}
+	if multiplicities := spectrum.Multiplicities(); !reflect.DeepEqual(multiplicities, []uint64{1, 3}) {
+		t.Errorf("Multiplicities() = %v", multiplicities)
+	}
+}
+
+func TestCounts_Merge(t *testing.T) {
+	first, _ := Count("ACGTA", 3)
+	second, _ := Count("TACGT", 3)
+
+	if err := first.Merge(second); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if got := first.Get("ACG"); got != 4 {
+		t.Errorf("merged count of ACG = %d, expected 4", got)
+	}
+
+	other, _ := Count("ACGTA", 4)
+	if err := first.Merge(other); !errors.Is(err, ErrKMismatch) {
+		t.Errorf("expected ErrKMismatch, got %v", err)
+	}
+}
+
+func TestCountSet(t *testing.T) {
+	records := []bioio.Record{
+		{ID: "chr1", Sequence: "ACGTACGT"},
+		{ID: "chr2", Sequence: "TTTTNAAAA"},
+		{ID: "chr1", Sequence: "ACG"},
+	}
+
+	byRecord, err := CountRecords(sets.NewDNASet(records), 3)
+	if err != nil {
+		t.Fatalf("CountRecords() error = %v", err)
+	}
+	if len(byRecord) != 2 || byRecord["chr1"].Get("ACG") != 5 || byRecord["chr2"].Get("AAA") != 4 {
+		t.Errorf("unexpected counts by record %v", byRecord)
+	}
+
+	merged, err := CountSet(sets.NewDNASet(records), 3)
+	if err != nil {
+		t.Fatalf("CountSet() error = %v", err)
+	}
+	if merged.Total() != 6+1+4 {
+		t.Errorf("CountSet() total = %d, expected 11", merged.Total())
+	}
+
+	rna, err := CountSet(sets.NewRNASet([]bioio.Record{{ID: "rna", Sequence: "UUUU"}}), 3)
+	if err != nil || rna.Get("AAA") != 2 {
+		t.Errorf("CountSet() of RNA set = %v, %v", rna, err)
+	}
+
+	_, err = CountSet(sets.NewDNASet([]bioio.Record{{ID: "bad", Sequence: "ACGU"}}), 3)
+	if !errors.Is(err, sequence.ErrDNAContainsU) {
+		t.Errorf("expected ErrDNAContainsU, got %v", err)
+	}
+}
+
+func TestCounts_File(t *testing.T) {
+	counts, _ := Count(randomDNA(20_000, 2), 21)
+
+	var buf bytes.Buffer
+	written, err := counts.WriteTo(&buf)
+	if err != nil || written != int64(buf.Len()) {
+		t.Fatalf("WriteTo() = %d, %v, buffer has %d bytes", written, err, buf.Len())
+	}
+
+	read, err := ReadCounts(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadCounts() error = %v", err)
+	}
+	if read.K != counts.K || !reflect.DeepEqual(read.counts, counts.counts) {
+		t.Errorf("ReadCounts() didn't restore the written counts")
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"magic":     []byte("FASTA-NOT-COUNTS"),
+		"truncated": buf.Bytes()[:buf.Len()-1],
+	} {
+		if _, err = ReadCounts(bytes.NewReader(data)); !errors.Is(err, ErrInvalidCountFile) {
+			t.Errorf("%s: expected ErrInvalidCountFile, got %v", name, err)
+		}
+	}
+}
+
+func BenchmarkCount(b *testing.B) {
+	seq := randomDNA(1_000_000, 3)
+	b.SetBytes(int64(len(seq)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Count(seq, 21); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
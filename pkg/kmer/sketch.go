@@ -0,0 +1,328 @@
+package kmer
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/dissipative/ribosome/pkg/sequence"
+	"github.com/dissipative/ribosome/pkg/sets"
+)
+
+// DefaultSketchSize is the default sketch size of Mash.
+const DefaultSketchSize = 1000
+
+var (
+	ErrInvalidSketchSize = errors.New("sketch size must be positive")
+	ErrSketchMismatch    = errors.New("sketches have different k or size")
+)
+
+// Sketch is a bottom-s MinHash sketch: the Size smallest hashes of the canonical k-mers of a genome. Sketches
+// estimate the Jaccard index of the k-mer sets of genomes without storing them. Hashes are not compatible with
+// Mash sketch files.
+type Sketch struct {
+	Name string
+	K    int
+	Size int
+	// Length is the number of k-mers added, it estimates the genome size for p-values.
+	Length uint64
+	// Hashes are sorted in increasing order.
+	Hashes []uint64
+}
+
+func NewSketch(name string, k, size int) (*Sketch, error) {
+	if k < 1 || k > MaxK {
+		return nil, ErrInvalidK
+	}
+	if size < 1 {
+		return nil, ErrInvalidSketchSize
+	}
+
+	return &Sketch{Name: name, K: k, Size: size}, nil
+}
+
+// hashKMer mixes the bits of an encoded k-mer with the finalizer of MurmurHash3, it is a bijection so distinct
+// k-mers never collide
+func hashKMer(kmer uint64) uint64 {
+	kmer ^= kmer >> 33
+	kmer *= 0xff51afd7ed558ccd
+	kmer ^= kmer >> 33
+	kmer *= 0xc4ceb9fe1a85ec53
+	kmer ^= kmer >> 33
+	return kmer
+}
+
+// maxHeap keeps the smallest hashes seen with the largest on top
+type maxHeap []uint64
+
+func (h maxHeap) Len() int           { return len(h) }
+func (h maxHeap) Less(i, j int) bool { return h[i] > h[j] }
+func (h maxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x any)        { *h = append(*h, x.(uint64)) }
+func (h *maxHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// bottomHashes collects the smallest distinct hashes
+type bottomHashes struct {
+	size     int
+	heap     maxHeap
+	included map[uint64]struct{}
+}
+
+func newBottomHashes(size int, hashes []uint64) *bottomHashes {
+	bottom := &bottomHashes{
+		size:     size,
+		heap:     append(maxHeap(nil), hashes...),
+		included: make(map[uint64]struct{}, size),
+	}
+	for _, hash := range hashes {
+		bottom.included[hash] = struct{}{}
+	}
+	heap.Init(&bottom.heap)
+
+	return bottom
+}
+
+func (b *bottomHashes) add(hash uint64) {
+	full := len(b.heap) == b.size
+	if full && hash >= b.heap[0] {
+		return
+	}
+	if _, ok := b.included[hash]; ok {
+		return
+	}
+
+	if full {
+		delete(b.included, b.heap[0])
+		b.heap[0] = hash
+		heap.Fix(&b.heap, 0)
+	} else {
+		heap.Push(&b.heap, hash)
+	}
+	b.included[hash] = struct{}{}
+}
+
+func (b *bottomHashes) sorted() []uint64 {
+	hashes := []uint64(b.heap)
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	return hashes
+}
+
+// Add adds the canonical k-mers of seq to the sketch, k-mers with ambiguous bases are skipped.
+// It fails on letters that are not IUPAC nucleotides.
+func (s *Sketch) Add(seq sequence.DNASequence) error {
+	bottom := newBottomHashes(s.Size, s.Hashes)
+
+	err := forEachCanonical(seq, s.K, func(kmer uint64) {
+		bottom.add(hashKMer(kmer))
+		s.Length++
+	})
+	if err != nil {
+		return err
+	}
+
+	s.Hashes = bottom.sorted()
+	return nil
+}
+
+// Merge adds the hashes of other, the result is the sketch of both genomes, e.g. of two contigs.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.K != other.K || s.Size != other.Size {
+		return fmt.Errorf("%w: k %d and %d, size %d and %d", ErrSketchMismatch, s.K, other.K, s.Size, other.Size)
+	}
+
+	bottom := newBottomHashes(s.Size, s.Hashes)
+	for _, hash := range other.Hashes {
+		bottom.add(hash)
+	}
+
+	s.Hashes = bottom.sorted()
+	s.Length += other.Length
+
+	return nil
+}
+
+// Distance is the comparison of two sketches.
+type Distance struct {
+	// Shared is the number of hashes shared by both sketches among the Compared smallest hashes of their union.
+	Shared   int
+	Compared int
+	Jaccard  float64
+	// Distance is the Mash distance, an estimate of the mutation rate between the genomes (Ondov et al., 2016).
+	Distance float64
+	// PValue is the probability of sharing as many hashes by chance given the genome sizes.
+	PValue float64
+}
+
+// Compare estimates the Jaccard index, Mash distance and p-value of the genomes of two sketches.
+func (s *Sketch) Compare(other *Sketch) (Distance, error) {
+	if s.K != other.K || s.Size != other.Size {
+		return Distance{}, fmt.Errorf("%w: k %d and %d, size %d and %d", ErrSketchMismatch, s.K, other.K, s.Size, other.Size)
+	}
+
+	// walk the smallest hashes of the union
+	shared, compared := 0, 0
+	i, j := 0, 0
+	for compared < s.Size && i < len(s.Hashes) && j < len(other.Hashes) {
+		switch a, b := s.Hashes[i], other.Hashes[j]; {
+		case a == b:
+			shared++
+			i++
+			j++
+		case a < b:
+			i++
+		default:
+			j++
+		}
+		compared++
+	}
+	// one sketch is exhausted, the rest of the union comes from the other
+	compared += minInt(s.Size-compared, len(s.Hashes)-i+len(other.Hashes)-j)
+
+	distance := Distance{Shared: shared, Compared: compared, Distance: 1, PValue: 1}
+	if compared == 0 {
+		return distance, nil
+	}
+
+	distance.Jaccard = float64(shared) / float64(compared)
+	if shared > 0 {
+		distance.Distance = -1 / float64(s.K) * math.Log(2*distance.Jaccard/(1+distance.Jaccard))
+	}
+	distance.PValue = mashPValue(shared, compared, s.K, s.Length, other.Length)
+
+	return distance, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// mashPValue returns the probability of x or more shared hashes among s when the k-mers of genomes of the given
+// sizes match only by chance
+func mashPValue(x, s, k int, lengthA, lengthB uint64) float64 {
+	if x == 0 || lengthA == 0 || lengthB == 0 {
+		return 1
+	}
+
+	// probability that a random k-mer is in a genome
+	kmerSpace := math.Pow(4, float64(k))
+	pA := 1 / (1 + kmerSpace/float64(lengthA))
+	pB := 1 / (1 + kmerSpace/float64(lengthB))
+	r := pA * pB / (pA + pB - pA*pB)
+	if r <= 0 {
+		return 0
+	}
+	if r >= 1 {
+		return 1
+	}
+
+	// binomial upper tail in log space
+	p := 0.
+	logS, _ := math.Lgamma(float64(s + 1))
+	for i := x; i <= s; i++ {
+		logI, _ := math.Lgamma(float64(i + 1))
+		logRest, _ := math.Lgamma(float64(s - i + 1))
+		p += math.Exp(logS - logI - logRest + float64(i)*math.Log(r) + float64(s-i)*math.Log1p(-r))
+	}
+
+	return math.Min(p, 1)
+}
+
+// SketchRecords sketches every record of the set on all CPUs, e.g. one genome per record, in the order of the
+// records. Records of RNA sets are read as their DNA copy.
+func SketchRecords(set *sets.Set, k, size int) ([]*Sketch, error) {
+	records := set.Records()
+	sketches := make([]*Sketch, len(records))
+
+	err := forEachRecord(set, func(i int, seq sequence.DNASequence) error {
+		sketch, err := NewSketch(records[i].ID, k, size)
+		if err != nil {
+			return err
+		}
+
+		sketches[i] = sketch
+		return sketch.Add(seq)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sketches, nil
+}
+
+// SketchSet returns a single sketch of all records of the set, e.g. of the contigs of one assembly.
+func SketchSet(set *sets.Set, name string, k, size int) (*Sketch, error) {
+	sketches, err := SketchRecords(set, k, size)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := NewSketch(name, k, size)
+	if err != nil {
+		return nil, err
+	}
+	for _, sketch := range sketches {
+		_ = merged.Merge(sketch)
+	}
+
+	return merged, nil
+}
+
+// AllVsAll compares every pair of sketches using all CPUs and returns the symmetric matrix of distances,
+// the diagonal holds the comparison of every sketch with itself.
+func AllVsAll(sketches []*Sketch) ([][]Distance, error) {
+	n := len(sketches)
+	distances := make([][]Distance, n)
+	for i := range distances {
+		distances[i] = make([]Distance, n)
+	}
+
+	rows := make(chan int)
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range rows {
+				for j := i; j < n; j++ {
+					distance, err := sketches[i].Compare(sketches[j])
+					if err != nil {
+						select {
+						case errs <- fmt.Errorf("%s and %s: %w", sketches[i].Name, sketches[j].Name, err):
+						default:
+						}
+						continue
+					}
+					distances[i][j], distances[j][i] = distance, distance
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		rows <- i
+	}
+	close(rows)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+		return distances, nil
+	}
+}
@@ -0,0 +1,183 @@
+package kmer
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+	"github.com/dissipative/ribosome/pkg/sequence"
+	"github.com/dissipative/ribosome/pkg/sets"
+)
+
+// mutate substitutes a fraction of the bases of seq
+func mutate(seq sequence.DNASequence, rate float64, seed int64) sequence.DNASequence {
+	random := rand.New(rand.NewSource(seed))
+	mutated := []byte(seq)
+	for i := range mutated {
+		if random.Float64() < rate {
+			mutated[i] = "ACGT"[(bytes.IndexByte([]byte("ACGT"), mutated[i])+1+random.Intn(3))%4]
+		}
+	}
+	return sequence.DNASequence(mutated)
+}
+
+func newTestSketch(t *testing.T, name string, seq sequence.DNASequence) *Sketch {
+	t.Helper()
+
+	sketch, err := NewSketch(name, 21, DefaultSketchSize)
+	if err != nil {
+		t.Fatalf("NewSketch() error = %v", err)
+	}
+	if err = sketch.Add(seq); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	return sketch
+}
+
+func TestSketch_Compare(t *testing.T) {
+	genome := randomDNA(200_000, 4)
+	sketch := newTestSketch(t, "genome", genome)
+
+	if len(sketch.Hashes) != DefaultSketchSize || sketch.Length != 200_000-20 {
+		t.Fatalf("unexpected sketch with %d hashes of %d k-mers", len(sketch.Hashes), sketch.Length)
+	}
+
+	tests := []struct {
+		name     string
+		other    *Sketch
+		jaccard  float64
+		distance float64
+		delta    float64
+		pValue   float64
+	}{
+		{name: "identical", other: newTestSketch(t, "copy", genome), jaccard: 1, distance: 0, pValue: 1e-100},
+		{name: "reverse-complement", other: newTestSketch(t, "rc", genome.ReverseComplement()), jaccard: 1, pValue: 1e-100},
+		{name: "mutated", other: newTestSketch(t, "mutated", mutate(genome, 0.01, 5)), distance: 0.01, delta: 0.003, pValue: 1e-100},
+		{name: "unrelated", other: newTestSketch(t, "unrelated", randomDNA(200_000, 6)), distance: 1, pValue: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance, err := sketch.Compare(tt.other)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+
+			if distance.Compared != DefaultSketchSize {
+				t.Errorf("Compared = %d, expected %d", distance.Compared, DefaultSketchSize)
+			}
+			if tt.jaccard > 0 && distance.Jaccard != tt.jaccard {
+				t.Errorf("Jaccard = %v, expected %v", distance.Jaccard, tt.jaccard)
+			}
+			if math.Abs(distance.Distance-tt.distance) > tt.delta {
+				t.Errorf("Distance = %v, expected %v±%v", distance.Distance, tt.distance, tt.delta)
+			}
+			if distance.PValue > tt.pValue {
+				t.Errorf("PValue = %v, expected at most %v", distance.PValue, tt.pValue)
+			}
+		})
+	}
+
+	other, _ := NewSketch("k15", 15, DefaultSketchSize)
+	if _, err := sketch.Compare(other); !errors.Is(err, ErrSketchMismatch) {
+		t.Errorf("expected ErrSketchMismatch, got %v", err)
+	}
+}
+
+func TestSketch_Merge(t *testing.T) {
+	first, second := randomDNA(50_000, 7), randomDNA(50_000, 8)
+
+	whole := newTestSketch(t, "whole", first+"N"+second)
+	merged := newTestSketch(t, "merged", first)
+	if err := merged.Merge(newTestSketch(t, "second", second)); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(merged.Hashes, whole.Hashes) || merged.Length != whole.Length {
+		t.Errorf("merged sketch differs from the sketch of the whole sequence")
+	}
+
+	small, _ := NewSketch("small", 21, 10)
+	if err := merged.Merge(small); !errors.Is(err, ErrSketchMismatch) {
+		t.Errorf("expected ErrSketchMismatch, got %v", err)
+	}
+}
+
+func TestSketchRecords_AllVsAll(t *testing.T) {
+	genome := randomDNA(100_000, 9)
+	records := []bioio.Record{
+		{ID: "a", Sequence: string(genome)},
+		{ID: "b", Sequence: string(mutate(genome, 0.02, 10))},
+		{ID: "c", Sequence: string(randomDNA(100_000, 11))},
+	}
+
+	sketches, err := SketchRecords(sets.NewDNASet(records), 21, 500)
+	if err != nil {
+		t.Fatalf("SketchRecords() error = %v", err)
+	}
+	if len(sketches) != 3 || sketches[1].Name != "b" {
+		t.Fatalf("unexpected sketches %v", sketches)
+	}
+
+	distances, err := AllVsAll(sketches)
+	if err != nil {
+		t.Fatalf("AllVsAll() error = %v", err)
+	}
+	for i := range distances {
+		if distances[i][i].Distance != 0 {
+			t.Errorf("distance of %s to itself = %v", sketches[i].Name, distances[i][i].Distance)
+		}
+		for j := range distances {
+			if distances[i][j] != distances[j][i] {
+				t.Errorf("distances of %d and %d are not symmetric", i, j)
+			}
+		}
+	}
+	if !(distances[0][1].Distance < distances[0][2].Distance) || distances[0][2].Distance != 1 {
+		t.Errorf("unexpected distances %+v", distances[0])
+	}
+
+	whole, err := SketchSet(sets.NewDNASet(records), "all", 21, 500)
+	if err != nil || whole.Length != 3*(100_000-20) {
+		t.Errorf("SketchSet() = %+v, %v", whole, err)
+	}
+
+	other, _ := NewSketch("k15", 15, 500)
+	if _, err = AllVsAll(append(sketches, other)); !errors.Is(err, ErrSketchMismatch) {
+		t.Errorf("expected ErrSketchMismatch, got %v", err)
+	}
+}
+
+func TestSketch_File(t *testing.T) {
+	sketches := []*Sketch{
+		newTestSketch(t, "first", randomDNA(10_000, 12)),
+		newTestSketch(t, "", randomDNA(100, 13)),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSketches(&buf, sketches); err != nil {
+		t.Fatalf("WriteSketches() error = %v", err)
+	}
+
+	read, err := ReadSketches(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadSketches() error = %v", err)
+	}
+	if !reflect.DeepEqual(read, sketches) {
+		t.Errorf("ReadSketches() didn't restore the written sketches")
+	}
+
+	for name, data := range map[string][]byte{
+		"magic":     []byte("RBKC-counts-not-sketches"),
+		"truncated": buf.Bytes()[:buf.Len()-1],
+	} {
+		if _, err = ReadSketches(bytes.NewReader(data)); !errors.Is(err, ErrInvalidSketchFile) {
+			t.Errorf("%s: expected ErrInvalidSketchFile, got %v", name, err)
+		}
+	}
+}
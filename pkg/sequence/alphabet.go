@@ -0,0 +1,113 @@
+package sequence
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Alphabet is a set of letters allowed in a sequence. Letters are matched case-insensitively.
+type Alphabet struct {
+	name    string
+	letters string
+	allowed [256]bool
+}
+
+const (
+	strictDNA          = "ACGT"
+	strictRNA          = "ACGU"
+	iupacCodes         = "RYSWKMBDHVN"
+	gapLetters         = "-"
+	standardAminoAcids = "ACDEFGHIKLMNPQRSTVWY"
+	// B, Z, J and X are ambiguity codes, U is selenocysteine, O is pyrrolysine and * is a stop
+	extendedAminoAcids = "BZJXUO*"
+)
+
+var (
+	DNAStrictAlphabet = NewAlphabet("strict DNA", strictDNA)
+	DNAIUPACAlphabet  = NewAlphabet("IUPAC DNA", strictDNA+iupacCodes)
+	DNAGappedAlphabet = NewAlphabet("gapped IUPAC DNA", strictDNA+iupacCodes+gapLetters)
+
+	RNAStrictAlphabet = NewAlphabet("strict RNA", strictRNA)
+	RNAIUPACAlphabet  = NewAlphabet("IUPAC RNA", strictRNA+iupacCodes)
+	RNAGappedAlphabet = NewAlphabet("gapped IUPAC RNA", strictRNA+iupacCodes+gapLetters)
+
+	ProteinStrictAlphabet = NewAlphabet("strict protein", standardAminoAcids)
+	ProteinIUPACAlphabet  = NewAlphabet("IUPAC protein", standardAminoAcids+extendedAminoAcids)
+	ProteinGappedAlphabet = NewAlphabet("gapped IUPAC protein", standardAminoAcids+extendedAminoAcids+gapLetters)
+)
+
+// NewAlphabet creates an alphabet of ASCII letters, both cases of every letter are allowed.
+func NewAlphabet(name, letters string) *Alphabet {
+	alphabet := &Alphabet{name: name, letters: strings.ToUpper(letters)}
+	for i := 0; i < len(letters); i++ {
+		alphabet.allowed[strings.ToUpper(letters[i : i+1])[0]] = true
+		alphabet.allowed[strings.ToLower(letters[i : i+1])[0]] = true
+	}
+
+	return alphabet
+}
+
+func (a *Alphabet) Name() string {
+	return a.name
+}
+
+// Letters returns the uppercase letters of the alphabet.
+func (a *Alphabet) Letters() string {
+	return a.letters
+}
+
+func (a *Alphabet) Contains(letter byte) bool {
+	return a.allowed[letter]
+}
+
+// Validate returns an *InvalidCharacterError for the first character of seq missing from the alphabet.
+func (a *Alphabet) Validate(seq string) error {
+	for i := 0; i < len(seq); i++ {
+		if !a.allowed[seq[i]] {
+			char, _ := utf8.DecodeRuneInString(seq[i:])
+			return &InvalidCharacterError{Alphabet: a.name, Position: i, Character: char}
+		}
+	}
+
+	return nil
+}
+
+// InvalidCharacterError reports a character that is not allowed by the alphabet of a sequence.
+// Position is a 0-based byte offset. Err optionally holds a more specific cause, e.g. ErrRNAContainsT.
+type InvalidCharacterError struct {
+	Alphabet  string
+	Position  int
+	Character rune
+	Err       error
+}
+
+func (e *InvalidCharacterError) Error() string {
+	msg := fmt.Sprintf("invalid character %q at position %d for %s alphabet", e.Character, e.Position, e.Alphabet)
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+
+	return msg
+}
+
+func (e *InvalidCharacterError) Unwrap() error {
+	return e.Err
+}
+
+// validateSequence checks input against the alphabet and wraps cause into the error if the offending
+// character is wrongLetter, e.g. T in an RNA sequence.
+func validateSequence(input string, alphabet *Alphabet, wrongLetter byte, cause error) error {
+	err := alphabet.Validate(input)
+	if err == nil {
+		return nil
+	}
+
+	invalid := err.(*InvalidCharacterError)
+	if unicode.ToUpper(invalid.Character) == rune(wrongLetter) {
+		invalid.Err = cause
+	}
+
+	return invalid
+}
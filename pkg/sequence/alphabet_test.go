@@ -0,0 +1,86 @@
+package sequence
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAlphabet_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		alphabet     *Alphabet
+		input        string
+		wantPosition int
+		wantChar     rune
+		wantErr      bool
+	}{
+		{name: "strict-dna", alphabet: DNAStrictAlphabet, input: "acgtACGT"},
+		{name: "strict-dna-ambiguous", alphabet: DNAStrictAlphabet, input: "ACGN", wantErr: true, wantPosition: 3, wantChar: 'N'},
+		{name: "iupac-dna", alphabet: DNAIUPACAlphabet, input: "ACGTRYSWKMBDHVN"},
+		{name: "iupac-dna-gap", alphabet: DNAIUPACAlphabet, input: "AC-GT", wantErr: true, wantPosition: 2, wantChar: '-'},
+		{name: "gapped-dna", alphabet: DNAGappedAlphabet, input: "AC-GT"},
+		{name: "dna-digit", alphabet: DNAGappedAlphabet, input: "ACG1", wantErr: true, wantPosition: 3, wantChar: '1'},
+		{name: "dna-j", alphabet: DNAGappedAlphabet, input: "AJ", wantErr: true, wantPosition: 1, wantChar: 'J'},
+		{name: "strict-rna", alphabet: RNAStrictAlphabet, input: "ACGU"},
+		{name: "strict-protein", alphabet: ProteinStrictAlphabet, input: "MKLVX", wantErr: true, wantPosition: 4, wantChar: 'X'},
+		{name: "iupac-protein", alphabet: ProteinIUPACAlphabet, input: "MKLVXBZJUO*"},
+		{name: "non-ascii", alphabet: ProteinGappedAlphabet, input: "MKé", wantErr: true, wantPosition: 2, wantChar: 'é'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.alphabet.Validate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			var invalid *InvalidCharacterError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected InvalidCharacterError, got %T", err)
+			}
+			if invalid.Position != tt.wantPosition || invalid.Character != tt.wantChar {
+				t.Errorf("got %q at position %d, expected %q at position %d",
+					invalid.Character, invalid.Position, tt.wantChar, tt.wantPosition)
+			}
+		})
+	}
+}
+
+func TestNewSequenceConstructors(t *testing.T) {
+	dna, err := NewDNASequence("acgtn-")
+	if err != nil || dna != "ACGTN-" {
+		t.Errorf("NewDNASequence() got = %s, %v", dna, err)
+	}
+
+	_, err = NewDNASequence("ACGU")
+	if !errors.Is(err, ErrDNAContainsU) {
+		t.Errorf("expected ErrDNAContainsU, got %v", err)
+	}
+
+	_, err = NewDNASequenceWithAlphabet("ACGN", DNAStrictAlphabet)
+	var invalid *InvalidCharacterError
+	if !errors.As(err, &invalid) || invalid.Position != 3 {
+		t.Errorf("expected invalid N at position 3, got %v", err)
+	}
+
+	_, err = NewRNASequence("AUGtAA")
+	if !errors.Is(err, ErrRNAContainsT) {
+		t.Errorf("expected ErrRNAContainsT, got %v", err)
+	}
+	if !errors.As(err, &invalid) || invalid.Position != 3 || invalid.Character != 't' {
+		t.Errorf("expected invalid t at position 3, got %v", err)
+	}
+
+	protein, err := NewProteinSequence("mkl*")
+	if err != nil || protein != "MKL*" {
+		t.Errorf("NewProteinSequence() got = %s, %v", protein, err)
+	}
+
+	_, err = NewProteinSequenceWithAlphabet("MKL*", ProteinStrictAlphabet)
+	if !errors.As(err, &invalid) || invalid.Character != '*' {
+		t.Errorf("expected invalid * for strict protein alphabet, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNoCodonForResidue = errors.New("no codon encodes residue")
+
+// CodonUsage maps uppercase RNA (or DNA) codons to their frequency, e.g. counts or per-thousand values
+// of a codon usage table. Codons missing from the map have frequency 0.
+type CodonUsage map[string]float64
+
+// Frequency returns the frequency of a codon whether it is stored as an RNA or a DNA codon.
+func (u CodonUsage) Frequency(codon string) float64 {
+	codon = strings.ToUpper(codon)
+	if frequency, ok := u[strings.ReplaceAll(codon, "T", "U")]; ok {
+		return frequency
+	}
+
+	return u[strings.ReplaceAll(codon, "U", "T")]
+}
+
+// SynonymousCodons returns the unambiguous codons encoding aa in the genetic code table order.
+// Stop codons are returned for '*'.
+func (c *CodonTable) SynonymousCodons(aa AminoAcid) []string {
+	var codons []string
+	for _, codon := range codonsInTableOrder(c.Codons) {
+		if c.Codons[codon] == aa && strings.Trim(codon, rnaBases) == "" {
+			codons = append(codons, codon)
+		}
+	}
+
+	return codons
+}
+
+// backTranslationCodons returns the codons of a residue, ambiguous residues give the codons of all their residues.
+func (c *CodonTable) backTranslationCodons(aa AminoAcid) []string {
+	residues, isAmbiguous := AmbiguousAminoAcidsMap[aa]
+	if !isAmbiguous {
+		return c.SynonymousCodons(aa)
+	}
+
+	var codons []string
+	for _, residue := range residues {
+		codons = append(codons, c.SynonymousCodons(residue)...)
+	}
+	return codons
+}
+
+// BackTranslate returns the fully degenerate DNA of p: every residue becomes the IUPAC codon covering all of
+// its codons in the table, e.g. Leu gives YTN and Ser gives WSN. The codon may also cover codons of other
+// residues. Gaps become '---'.
+func (p ProteinSequence) BackTranslate(codonTable *CodonTable) (DNASequence, error) {
+	degenerate := make(map[AminoAcid]string)
+
+	var sb strings.Builder
+	sb.Grow(len(p) * 3)
+
+	for i, residue := range []AminoAcid(strings.ToUpper(string(p))) {
+		if residue == '-' {
+			sb.WriteString("---")
+			continue
+		}
+
+		codon, ok := degenerate[residue]
+		if !ok {
+			codons := codonTable.backTranslationCodons(residue)
+			if len(codons) == 0 {
+				return "", fmt.Errorf("%w %q at position %d in codon table no. %d", ErrNoCodonForResidue, residue, i, codonTable.ID)
+			}
+
+			codon = degenerateCodon(codons)
+			degenerate[residue] = codon
+		}
+
+		sb.WriteString(codon)
+	}
+
+	return DNASequence(sb.String()), nil
+}
+
+// BackTranslateWithUsage returns the DNA of p using the most frequent codon of every residue.
+// Ties and residues without usage data are resolved by the genetic code table order.
+func (p ProteinSequence) BackTranslateWithUsage(codonTable *CodonTable, usage CodonUsage) (DNASequence, error) {
+	preferred := make(map[AminoAcid]string)
+
+	var sb strings.Builder
+	sb.Grow(len(p) * 3)
+
+	for i, residue := range []AminoAcid(strings.ToUpper(string(p))) {
+		if residue == '-' {
+			sb.WriteString("---")
+			continue
+		}
+
+		codon, ok := preferred[residue]
+		if !ok {
+			codons := codonTable.backTranslationCodons(residue)
+			if len(codons) == 0 {
+				return "", fmt.Errorf("%w %q at position %d in codon table no. %d", ErrNoCodonForResidue, residue, i, codonTable.ID)
+			}
+
+			codon = codons[0]
+			for _, candidate := range codons[1:] {
+				if usage.Frequency(candidate) > usage.Frequency(codon) {
+					codon = candidate
+				}
+			}
+
+			codon = strings.ReplaceAll(codon, "U", "T")
+			preferred[residue] = codon
+		}
+
+		sb.WriteString(codon)
+	}
+
+	return DNASequence(sb.String()), nil
+}
+
+// degenerateCodon returns the DNA codon with the IUPAC code of all bases found at each position of codons
+func degenerateCodon(codons []string) string {
+	var masks [3]byte
+	for _, codon := range codons {
+		for i := 0; i < 3; i++ {
+			masks[i] |= rnaLetterMasks[codon[i]]
+		}
+	}
+
+	return string([]byte{dnaMaskLetters[masks[0]], dnaMaskLetters[masks[1]], dnaMaskLetters[masks[2]]})
+}
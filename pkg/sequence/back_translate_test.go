@@ -0,0 +1,79 @@
+package sequence
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCodonTable_SynonymousCodons(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+
+	if got := standardTable.SynonymousCodons('L'); !reflect.DeepEqual(got, []string{"UUA", "UUG", "CUU", "CUC", "CUA", "CUG"}) {
+		t.Errorf("SynonymousCodons('L') = %v", got)
+	}
+	if got := standardTable.SynonymousCodons('*'); !reflect.DeepEqual(got, []string{"UAA", "UAG", "UGA"}) {
+		t.Errorf("SynonymousCodons('*') = %v", got)
+	}
+}
+
+func TestProteinSequence_BackTranslate(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+	mitoTable, _ := GetCodonTable(2)
+
+	tests := []struct {
+		name       string
+		protein    ProteinSequence
+		codonTable *CodonTable
+		expected   DNASequence
+		wantErr    bool
+	}{
+		{name: "single-codon-residues", protein: "MW", codonTable: &standardTable, expected: "ATGTGG"},
+		{name: "six-fold-degenerate", protein: "LSR", codonTable: &standardTable, expected: "YTNWSNMGN"},
+		{name: "two-fold-degenerate", protein: "FKE*", codonTable: &standardTable, expected: "TTYAARGARTRR"},
+		{name: "ambiguous-residues", protein: "BZX", codonTable: &standardTable, expected: "RAYSARNNN"},
+		{name: "lowercase-and-gap", protein: "m-w", codonTable: &standardTable, expected: "ATG---TGG"},
+		{name: "other-table", protein: "W*", codonTable: &mitoTable, expected: "TGRWRR"},
+		{name: "unknown-residue", protein: "MO", codonTable: &standardTable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.protein.BackTranslate(tt.codonTable)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BackTranslate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrNoCodonForResidue) {
+				t.Errorf("expected ErrNoCodonForResidue, got %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("BackTranslate() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProteinSequence_BackTranslateWithUsage(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+	usage := CodonUsage{
+		"CUG": 52.6, "UUA": 13.9, "UUG": 13.7, "CUU": 11.0, "CUC": 11.0, "CUA": 3.9,
+		"AAA": 33.6, "AAG": 10.3,
+		// DNA codons are accepted too
+		"TAA": 0.2, "TGA": 2.0, "TAG": 1.0,
+	}
+
+	got, err := ProteinSequence("MLKC*").BackTranslateWithUsage(&standardTable, usage)
+	if err != nil {
+		t.Fatalf("BackTranslateWithUsage() error = %v", err)
+	}
+
+	// Cys has no usage data, the first codon in table order is used
+	if expected := DNASequence("ATGCTGAAATGTTGA"); got != expected {
+		t.Errorf("BackTranslateWithUsage() = %s, expected %s", got, expected)
+	}
+
+	protein, _ := got.TranscribeCoding().Translate(&standardTable)
+	if protein != "MLKC*" {
+		t.Errorf("back-translated DNA translates to %s", protein)
+	}
+}
@@ -0,0 +1,274 @@
+package sequence
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+var ErrNoInformativeCodons = errors.New("no informative codons")
+
+// missingCodonCount is the count given to codons absent from the reference set when computing relative
+// adaptiveness, so a single unseen codon doesn't make the CAI of a gene 0 (Sharp & Li, 1987).
+const missingCodonCount = 0.5
+
+// RelativeAdaptiveness returns the relative adaptiveness w of every codon: its count divided by the count of
+// the most used synonymous codon in the reference genes of t. Stops, amino acids with a single codon and
+// amino acids never seen in the reference are left out.
+func (t *CodonUsageTable) RelativeAdaptiveness() CodonUsage {
+	weights := make(CodonUsage)
+
+	for aa, codons := range t.synonymousFamilies() {
+		if aa == '*' || len(codons) < 2 {
+			continue
+		}
+
+		maxCount := 0
+		for _, codon := range codons {
+			if t.Counts[codon] > maxCount {
+				maxCount = t.Counts[codon]
+			}
+		}
+		if maxCount == 0 {
+			continue
+		}
+
+		for _, codon := range codons {
+			weights[codon] = math.Max(float64(t.Counts[codon]), missingCodonCount) / float64(maxCount)
+		}
+	}
+
+	return weights
+}
+
+// CAI returns the codon adaptation index of cds against the reference genes of t.
+func (t *CodonUsageTable) CAI(cds RNASequence) (float64, error) {
+	return CAI(cds, t.RelativeAdaptiveness())
+}
+
+// CAI returns the geometric mean of the weights of the codons of cds. Codons without a weight, e.g. stops,
+// Met and Trp codons or codons with ambiguous bases, are skipped.
+func CAI(cds RNASequence, weights CodonUsage) (float64, error) {
+	logSum, n := 0., 0
+	for codon, count := range CountCodons(cds) {
+		w, ok := weights[codon]
+		if !ok || w <= 0 {
+			continue
+		}
+
+		logSum += float64(count) * math.Log(w)
+		n += count
+	}
+
+	if n == 0 {
+		return 0, ErrNoInformativeCodons
+	}
+
+	return math.Exp(logSum / float64(n)), nil
+}
+
+// wobblePenalties are the selective constraints s of codon-anticodon pairings by the third codon base and the
+// first anticodon base (dos Reis et al., 2004). tRNA genes with A at the first anticodon position are assumed
+// to be modified to inosine.
+var wobblePenalties = map[byte]map[byte]float64{
+	'U': {'A': 0, 'G': 0.41},
+	'C': {'G': 0, 'A': 0.28},
+	'A': {'U': 0, 'A': 0.9999},
+	'G': {'C': 0, 'U': 0.68},
+}
+
+var rnaComplements = map[byte]byte{'A': 'U', 'U': 'A', 'G': 'C', 'C': 'G'}
+
+// TRNAAdaptiveness returns the tRNA adaptation weights of the sense codons of codonTable from the tRNA gene copy
+// numbers of a genome, keyed by 5'->3' anticodon (RNA or DNA). Met codons are left out. Codons read by no tRNA get
+// the geometric mean of the other weights.
+func TRNAAdaptiveness(codonTable *CodonTable, tRNACopies map[string]int) (CodonUsage, error) {
+	copies := make(map[string]int)
+	for anticodon, count := range tRNACopies {
+		copies[strings.ReplaceAll(strings.ToUpper(anticodon), "T", "U")] += count
+	}
+
+	absolute := make(map[string]float64)
+	maxWeight := 0.
+	for _, codon := range unambiguousCodons(codonTable) {
+		aa := codonTable.Codons[codon]
+		if aa == '*' || aa == 'M' {
+			continue
+		}
+
+		// anticodon positions 2 and 3 pair with the first two codon bases
+		suffix := string([]byte{rnaComplements[codon[1]], rnaComplements[codon[0]]})
+
+		weight := 0.
+		for first, s := range wobblePenalties[codon[2]] {
+			weight += (1 - s) * float64(copies[string(first)+suffix])
+		}
+
+		absolute[codon] = weight
+		maxWeight = math.Max(maxWeight, weight)
+	}
+
+	if maxWeight == 0 {
+		return nil, ErrNoInformativeCodons
+	}
+
+	weights := make(CodonUsage)
+	logSum, n := 0., 0
+	for codon, weight := range absolute {
+		if weight > 0 {
+			weights[codon] = weight / maxWeight
+			logSum += math.Log(weights[codon])
+			n++
+		}
+	}
+
+	mean := math.Exp(logSum / float64(n))
+	for codon, weight := range absolute {
+		if weight == 0 {
+			weights[codon] = mean
+		}
+	}
+
+	return weights, nil
+}
+
+// TAI returns the tRNA adaptation index of cds, the geometric mean of the weights computed by TRNAAdaptiveness.
+func TAI(cds RNASequence, weights CodonUsage) (float64, error) {
+	return CAI(cds, weights)
+}
+
+// ENC returns the effective number of codons (Wright, 1990), from 20 when a single codon is used for every
+// amino acid to the number of sense codons when synonymous codons are used uniformly. Homozygosity is averaged
+// over amino acids with the same number of synonymous codons; when no amino acid of a class is seen twice, Ile
+// (the 3-codon class) is estimated from the 2- and 4-codon classes and other classes are assumed uniform.
+func (c CodonCounts) ENC(codonTable *CodonTable) float64 {
+	classSize := make(map[int]int)
+	homozygosity := make(map[int]float64)
+	seen := make(map[int]int)
+	senseCodons := 0
+
+	for aa, codons := range synonymousFamilies(codonTable) {
+		if aa == '*' {
+			continue
+		}
+
+		k := len(codons)
+		classSize[k]++
+		senseCodons += k
+
+		n := 0
+		for _, codon := range codons {
+			n += c[codon]
+		}
+		if n < 2 {
+			continue
+		}
+
+		sumSquares := 0.
+		for _, codon := range codons {
+			p := float64(c[codon]) / float64(n)
+			sumSquares += p * p
+		}
+
+		f := (float64(n)*sumSquares - 1) / float64(n-1)
+		if f > 0 {
+			homozygosity[k] += f
+			seen[k]++
+		}
+	}
+
+	average := make(map[int]float64)
+	for k, f := range homozygosity {
+		average[k] = f / float64(seen[k])
+	}
+	if _, ok := average[3]; !ok && classSize[3] > 0 && average[2] > 0 && average[4] > 0 {
+		average[3] = (average[2] + average[4]) / 2
+	}
+
+	enc := 0.
+	for k, size := range classSize {
+		switch f, ok := average[k]; {
+		case k == 1:
+			enc += float64(size)
+		case ok:
+			enc += float64(size) / f
+		default:
+			enc += float64(size * k)
+		}
+	}
+
+	return math.Min(enc, float64(senseCodons))
+}
+
+// GC3s returns the fraction of G and C at the third position of synonymous codons, i.e. excluding stops
+// and amino acids with a single codon.
+func (c CodonCounts) GC3s(codonTable *CodonTable) float64 {
+	gc, total := 0, 0
+	for aa, codons := range synonymousFamilies(codonTable) {
+		if aa == '*' || len(codons) < 2 {
+			continue
+		}
+
+		for _, codon := range codons {
+			total += c[codon]
+			if codon[2] == 'G' || codon[2] == 'C' {
+				gc += c[codon]
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(gc) / float64(total)
+}
+
+// OptimalCodons returns the most used codon of every amino acid with synonymous codons in the reference genes,
+// a common stand-in for optimal codons when expression data is not available.
+func (t *CodonUsageTable) OptimalCodons() []string {
+	weights := t.RelativeAdaptiveness()
+
+	var optimal []string
+	for _, codon := range unambiguousCodons(t.CodonTable) {
+		if w, ok := weights[codon]; ok && w == 1 {
+			optimal = append(optimal, codon)
+		}
+	}
+
+	return optimal
+}
+
+// Fop returns the frequency of optimal codons: the number of optimal codons divided by the number of codons
+// of amino acids that have an optimal codon. Stops and amino acids with a single codon are not counted.
+func (c CodonCounts) Fop(codonTable *CodonTable, optimal []string) float64 {
+	isOptimal := make(map[string]bool)
+	for _, codon := range optimal {
+		isOptimal[strings.ReplaceAll(strings.ToUpper(codon), "T", "U")] = true
+	}
+
+	optimalCount, total := 0, 0
+	for aa, codons := range synonymousFamilies(codonTable) {
+		if aa == '*' || len(codons) < 2 {
+			continue
+		}
+
+		hasOptimal, familyTotal, familyOptimal := false, 0, 0
+		for _, codon := range codons {
+			familyTotal += c[codon]
+			if isOptimal[codon] {
+				hasOptimal = true
+				familyOptimal += c[codon]
+			}
+		}
+
+		if hasOptimal {
+			optimalCount += familyOptimal
+			total += familyTotal
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(optimalCount) / float64(total)
+}
@@ -0,0 +1,136 @@
+package sequence
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCodonUsageTable_CAI(t *testing.T) {
+	table := newTestCodonUsageTable(t)
+
+	weights := table.RelativeAdaptiveness()
+	for codon, expected := range map[string]float64{
+		"AAA": 1,
+		"AAG": 0.5,
+		"CUG": 1,
+		"UUA": 1 / 3.,
+		"CUU": 0.5 / 3., // unseen codon of a seen amino acid
+	} {
+		if math.Abs(weights[codon]-expected) > 1e-9 {
+			t.Errorf("w[%s] = %v, expected %v", codon, weights[codon], expected)
+		}
+	}
+	for _, codon := range []string{"AUG", "UAA", "GGG"} {
+		if _, ok := weights[codon]; ok {
+			t.Errorf("expected no weight for %s", codon)
+		}
+	}
+
+	cai, err := table.CAI("AUGAAACUGUAA")
+	if err != nil || math.Abs(cai-1) > 1e-9 {
+		t.Errorf("CAI() = %v, %v, expected 1", cai, err)
+	}
+
+	cai, err = table.CAI("AUGAAGUUAUAA")
+	if expected := math.Sqrt(0.5 / 3.); err != nil || math.Abs(cai-expected) > 1e-9 {
+		t.Errorf("CAI() = %v, %v, expected %v", cai, err, expected)
+	}
+
+	if _, err = table.CAI("AUGUGGUAA"); !errors.Is(err, ErrNoInformativeCodons) {
+		t.Errorf("expected ErrNoInformativeCodons, got %v", err)
+	}
+}
+
+func TestTRNAAdaptiveness(t *testing.T) {
+	standardTable, err := GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("GetCodonTable() error = %v", err)
+	}
+
+	// Phe tRNA GAA reads UUC and, by wobble, UUU; Lys tRNA UUU reads AAA and AAG
+	weights, err := TRNAAdaptiveness(&standardTable, map[string]int{"GAA": 2, "TTT": 1})
+	if err != nil {
+		t.Fatalf("TRNAAdaptiveness() error = %v", err)
+	}
+
+	for codon, expected := range map[string]float64{
+		"UUC": 1,
+		"UUU": 0.59,
+		"AAA": 0.5,
+		"AAG": 0.32 / 2,
+	} {
+		if math.Abs(weights[codon]-expected) > 1e-9 {
+			t.Errorf("w[%s] = %v, expected %v", codon, weights[codon], expected)
+		}
+	}
+
+	mean := math.Pow(1*0.59*0.5*0.16, 1/4.)
+	if math.Abs(weights["GGG"]-mean) > 1e-9 {
+		t.Errorf("w[GGG] = %v, expected geometric mean %v", weights["GGG"], mean)
+	}
+	if _, ok := weights["AUG"]; ok {
+		t.Errorf("expected no weight for AUG")
+	}
+
+	tai, err := TAI("AUGUUCAAAUAA", weights)
+	if expected := math.Sqrt(0.5); err != nil || math.Abs(tai-expected) > 1e-9 {
+		t.Errorf("TAI() = %v, %v, expected %v", tai, err, expected)
+	}
+
+	if _, err = TRNAAdaptiveness(&standardTable, nil); !errors.Is(err, ErrNoInformativeCodons) {
+		t.Errorf("expected ErrNoInformativeCodons, got %v", err)
+	}
+}
+
+func TestCodonCounts_ENC(t *testing.T) {
+	standardTable, err := GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("GetCodonTable() error = %v", err)
+	}
+
+	// every amino acid encoded by a single codon
+	single := make(CodonCounts)
+	// every synonymous codon used equally
+	uniform := make(CodonCounts)
+	for aa, codons := range synonymousFamilies(&standardTable) {
+		if aa == '*' {
+			continue
+		}
+		single[codons[0]] = 10
+		for _, codon := range codons {
+			uniform[codon] = 10
+		}
+	}
+
+	if enc := single.ENC(&standardTable); math.Abs(enc-20) > 1e-9 {
+		t.Errorf("ENC() of single codon usage = %v, expected 20", enc)
+	}
+	if enc := uniform.ENC(&standardTable); math.Abs(enc-61) > 1e-9 {
+		t.Errorf("ENC() of uniform codon usage = %v, expected 61", enc)
+	}
+	if enc := (CodonCounts{}).ENC(&standardTable); enc != 61 {
+		t.Errorf("ENC() without data = %v, expected 61", enc)
+	}
+}
+
+func TestCodonCounts_GC3sAndFop(t *testing.T) {
+	table := newTestCodonUsageTable(t)
+	counts := CountCodons("AUGAAAAAGCUGUUAUGGUAA")
+
+	// AAA, AAG, CUG, UUA: AUG, UGG and the stop are not synonymous codons
+	if gc3s := counts.GC3s(table.CodonTable); gc3s != 0.5 {
+		t.Errorf("GC3s() = %v, expected 0.5", gc3s)
+	}
+
+	optimal := table.OptimalCodons()
+	if len(optimal) != 2 || optimal[0] != "CUG" || optimal[1] != "AAA" {
+		t.Errorf("OptimalCodons() = %v, expected [CUG AAA]", optimal)
+	}
+	if fop := counts.Fop(table.CodonTable, optimal); fop != 0.5 {
+		t.Errorf("Fop() = %v, expected 0.5", fop)
+	}
+	if fop := counts.Fop(table.CodonTable, []string{"AAG"}); fop != 0.5 {
+		t.Errorf("Fop() with DNA codon list = %v, expected 0.5", fop)
+	}
+}
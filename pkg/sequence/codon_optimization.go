@@ -0,0 +1,434 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+var ErrUnsatisfiableConstraints = errors.New("codon optimization constraints cannot be satisfied")
+
+// maxReportedViolations limits the violations listed by ErrUnsatisfiableConstraints errors
+const maxReportedViolations = 10
+
+// siteCost is the cost of an occurrence of an avoided site, high enough to take precedence over
+// homopolymer and GC deviations that are measured in bases
+const siteCost = 1000
+
+// CodonOptimizationOptions are the constraints of OptimizeCodons, zero values disable a constraint.
+type CodonOptimizationOptions struct {
+	// AvoidSites are DNA motifs, e.g. restriction sites, that must not occur on either strand. IUPAC codes are allowed.
+	AvoidSites []string
+	// MaxHomopolymer is the longest allowed run of a single base.
+	MaxHomopolymer int
+	// GCWindow is the size of the sliding window whose GC content must stay within MinGC and MaxGC (fractions,
+	// MaxGC 0 means 1). Sequences shorter than the window are checked as a whole.
+	GCWindow     int
+	MinGC, MaxGC float64
+	// MinCodonWeight excludes rare codons: codons used less than MinCodonWeight times as often as the most used
+	// synonymous codon. The most used codon is always kept.
+	MinCodonWeight float64
+	// MaxIterations limits the number of codon replacements, 10 per codon by default.
+	MaxIterations int
+}
+
+// weightedCodon is a DNA codon with its usage relative to the most used synonymous codon
+type weightedCodon struct {
+	codon  string
+	weight float64
+}
+
+type codonOptimizer struct {
+	options    CodonOptimizationOptions
+	sites      [][]byte // IUPAC masks of the avoided sites and their reverse complements
+	siteNames  []string // the avoided site of every mask
+	candidates [][]weightedCodon
+	choices    []int // index of the chosen candidate of every codon
+	seq        []byte
+}
+
+// OptimizeCodons returns the DNA of p for expression in a host with the given codon usage. Every residue starts with
+// its most used codon, then codons overlapping a violated constraint are replaced one at a time by the synonymous
+// codon that reduces the violation most, preferring frequent codons, which keeps the CAI high.
+// When no replacement reduces a remaining violation or MaxIterations is reached, the error wraps
+// ErrUnsatisfiableConstraints and lists the constraints that are still violated.
+func (p ProteinSequence) OptimizeCodons(codonTable *CodonTable, usage CodonUsage, options CodonOptimizationOptions) (DNASequence, error) {
+	optimizer, err := newCodonOptimizer(p, codonTable, usage, options)
+	if err != nil {
+		return "", err
+	}
+
+	maxIterations := options.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = 10 * len(p)
+	}
+
+	// violations are repaired from left to right, a replacement can only add violations within the range it
+	// affects, so the next scan resumes there instead of at the start of the sequence
+	from := 0
+	for i := 0; ; i++ {
+		v, violated := optimizer.nextViolation(from)
+		if !violated {
+			break
+		}
+
+		codon := -1
+		if i < maxIterations {
+			codon = optimizer.repair(v.start, v.end)
+		}
+		if codon < 0 {
+			return "", optimizer.unsatisfiable(i)
+		}
+
+		from, _ = optimizer.affectedRange(codon)
+		if v.start < from {
+			from = v.start
+		}
+	}
+
+	return DNASequence(optimizer.seq), nil
+}
+
+func newCodonOptimizer(p ProteinSequence, codonTable *CodonTable, usage CodonUsage, options CodonOptimizationOptions) (*codonOptimizer, error) {
+	if options.MaxGC == 0 {
+		options.MaxGC = 1
+	}
+
+	optimizer := &codonOptimizer{
+		options: options,
+		choices: make([]int, len(p)),
+		seq:     make([]byte, 0, len(p)*3),
+	}
+
+	for _, site := range options.AvoidSites {
+		if err := DNAIUPACAlphabet.Validate(site); err != nil {
+			return nil, fmt.Errorf("invalid site %q: %w", site, err)
+		}
+		if site == "" {
+			return nil, errors.New("empty site")
+		}
+
+		masks := make([]byte, len(site))
+		for i := range site {
+			masks[i] = byte(packedMasks[site[i]])
+		}
+
+		optimizer.sites = append(optimizer.sites, masks)
+		optimizer.siteNames = append(optimizer.siteNames, strings.ToUpper(site))
+		if reverse := reverseComplementMaskSlice(masks); string(reverse) != string(masks) {
+			optimizer.sites = append(optimizer.sites, reverse)
+			optimizer.siteNames = append(optimizer.siteNames, strings.ToUpper(site))
+		}
+	}
+
+	weighted := make(map[AminoAcid][]weightedCodon)
+	for i, residue := range []AminoAcid(strings.ToUpper(string(p))) {
+		candidates, ok := weighted[residue]
+		if !ok {
+			codons := codonTable.backTranslationCodons(residue)
+			if len(codons) == 0 {
+				return nil, fmt.Errorf("%w %q at position %d in codon table no. %d", ErrNoCodonForResidue, residue, i, codonTable.ID)
+			}
+
+			candidates = weightCodons(codons, usage, options.MinCodonWeight)
+			weighted[residue] = candidates
+		}
+
+		optimizer.candidates = append(optimizer.candidates, candidates)
+		optimizer.seq = append(optimizer.seq, candidates[0].codon...)
+	}
+
+	return optimizer, nil
+}
+
+// weightCodons sorts codons by usage, most used first, and drops the rare ones
+func weightCodons(codons []string, usage CodonUsage, minWeight float64) []weightedCodon {
+	maxFrequency := 0.
+	for _, codon := range codons {
+		maxFrequency = math.Max(maxFrequency, usage.Frequency(codon))
+	}
+
+	var weighted []weightedCodon
+	for _, codon := range codons {
+		weight := 1.
+		if maxFrequency > 0 {
+			weight = usage.Frequency(codon) / maxFrequency
+		}
+		weighted = append(weighted, weightedCodon{codon: strings.ReplaceAll(codon, "U", "T"), weight: weight})
+	}
+
+	// stable, so ties keep the genetic code table order
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].weight > weighted[j].weight
+	})
+
+	kept := weighted[:1]
+	for _, candidate := range weighted[1:] {
+		if candidate.weight >= minWeight && candidate.weight > 0 {
+			kept = append(kept, candidate)
+		}
+	}
+
+	return kept
+}
+
+func reverseComplementMaskSlice(masks []byte) []byte {
+	reverse := make([]byte, len(masks))
+	for i, mask := range masks {
+		// reverse the bit order: A(1)<->T(8), C(2)<->G(4)
+		reverse[len(masks)-1-i] = (mask&1)<<3 | (mask&2)<<1 | (mask&4)>>1 | (mask&8)>>3
+	}
+	return reverse
+}
+
+// repair replaces the codon overlapping seq[start:end] that reduces the cost of its surroundings most.
+// It returns the replaced codon, -1 when no replacement reduces the cost.
+func (o *codonOptimizer) repair(start, end int) int {
+	bestCodon, bestChoice := -1, 0
+	bestDelta, bestWeight := 0., 0.
+
+	for codon := start / 3; codon*3 < end; codon++ {
+		current := o.choices[codon]
+		from, to := o.affectedRange(codon)
+		before := o.cost(from, to)
+
+		for choice, candidate := range o.candidates[codon] {
+			if choice == current {
+				continue
+			}
+
+			o.setCodon(codon, choice)
+			delta := o.cost(from, to) - before
+			o.setCodon(codon, current)
+
+			if delta < bestDelta || delta == bestDelta && bestCodon >= 0 && candidate.weight > bestWeight {
+				bestCodon, bestChoice, bestDelta, bestWeight = codon, choice, delta, candidate.weight
+			}
+		}
+	}
+
+	if bestCodon >= 0 {
+		o.setCodon(bestCodon, bestChoice)
+	}
+
+	return bestCodon
+}
+
+func (o *codonOptimizer) setCodon(codon, choice int) {
+	o.choices[codon] = choice
+	copy(o.seq[codon*3:], o.candidates[codon][choice].codon)
+}
+
+// affectedRange returns the part of the sequence whose cost can change when the codon is replaced
+func (o *codonOptimizer) affectedRange(codon int) (int, int) {
+	span := o.gcWindow()
+	for _, site := range o.sites {
+		if len(site) > span {
+			span = len(site)
+		}
+	}
+
+	from, to := codon*3-span, codon*3+3+span
+	if from < 0 {
+		from = 0
+	}
+	if to > len(o.seq) {
+		to = len(o.seq)
+	}
+
+	return from, to
+}
+
+func (o *codonOptimizer) gcWindow() int {
+	if o.options.GCWindow > len(o.seq) {
+		return len(o.seq)
+	}
+	return o.options.GCWindow
+}
+
+// cost measures the violations overlapping seq[from:to]: avoided sites, bases of homopolymer runs beyond
+// the limit and bases of GC windows outside the allowed range
+func (o *codonOptimizer) cost(from, to int) float64 {
+	cost := 0.
+
+	for _, site := range o.sites {
+		for i := from - len(site) + 1; i < to; i++ {
+			if o.siteAt(site, i) {
+				cost += siteCost
+			}
+		}
+	}
+
+	if maxRun := o.options.MaxHomopolymer; maxRun > 0 && to > from {
+		// whole runs are measured, a replaced codon can join runs reaching beyond the range
+		for from > 0 && o.seq[from-1] == o.seq[from] {
+			from--
+		}
+		for to < len(o.seq) && o.seq[to] == o.seq[to-1] {
+			to++
+		}
+
+		run := 1
+		for i := from + 1; i <= to; i++ {
+			if i < to && o.seq[i] == o.seq[i-1] {
+				run++
+				continue
+			}
+			if run > maxRun {
+				cost += float64(run - maxRun)
+			}
+			run = 1
+		}
+	}
+
+	if window := o.gcWindow(); window > 0 {
+		first, last := from-window+1, to-1
+		if first < 0 {
+			first = 0
+		}
+		if last > len(o.seq)-window {
+			last = len(o.seq) - window
+		}
+
+		minGC, maxGC := o.options.MinGC*float64(window), o.options.MaxGC*float64(window)
+		gc := 0
+		if first <= last {
+			gc = gcCount(o.seq[first : first+window])
+		}
+		for i := first; i <= last; i++ {
+			if i > first {
+				gc += gcCount(o.seq[i+window-1:i+window]) - gcCount(o.seq[i-1:i])
+			}
+			cost += math.Max(0, minGC-float64(gc)) + math.Max(0, float64(gc)-maxGC)
+		}
+	}
+
+	return cost
+}
+
+func (o *codonOptimizer) siteAt(site []byte, i int) bool {
+	if i < 0 || i+len(site) > len(o.seq) {
+		return false
+	}
+
+	for j, mask := range site {
+		if byte(packedMasks[o.seq[i+j]])&mask == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// violation is a violated constraint in seq[start:end]
+type violation struct {
+	constraint string
+	start, end int
+}
+
+func (v violation) String() string {
+	return fmt.Sprintf("%s at %d-%d", v.constraint, v.start+1, v.end)
+}
+
+// scan calls fn with the violations starting at or after from, ordered by the position where they are found,
+// until fn returns false. Homopolymer runs reaching back before from are reported whole, once per run.
+func (o *codonOptimizer) scan(from int, fn func(violation) bool) {
+	if from >= len(o.seq) {
+		return
+	}
+
+	maxRun, window := o.options.MaxHomopolymer, o.gcWindow()
+	runStart := from
+	for runStart > 0 && o.seq[runStart-1] == o.seq[from] {
+		runStart--
+	}
+	gc := 0
+	if window > 0 && from+window <= len(o.seq) {
+		gc = gcCount(o.seq[from : from+window])
+	}
+
+	for i := from; i < len(o.seq); i++ {
+		if i > from && o.seq[i] != o.seq[i-1] {
+			runStart = i
+		}
+		if run := i - runStart + 1; maxRun > 0 && run > maxRun && (run == maxRun+1 || i == from) {
+			end := i + 1
+			for end < len(o.seq) && o.seq[end] == o.seq[i] {
+				end++
+			}
+			if !fn(violation{fmt.Sprintf("homopolymer longer than %d", maxRun), runStart, end}) {
+				return
+			}
+		}
+
+		for k, site := range o.sites {
+			if o.siteAt(site, i) && !fn(violation{"site " + o.siteNames[k], i, i + len(site)}) {
+				return
+			}
+		}
+
+		if window > 0 && i+window <= len(o.seq) {
+			if i > from {
+				gc += gcCount(o.seq[i+window-1:i+window]) - gcCount(o.seq[i-1:i])
+			}
+			fraction := float64(gc) / float64(window)
+			if (fraction < o.options.MinGC || fraction > o.options.MaxGC) && !fn(violation{"GC content", i, i + window}) {
+				return
+			}
+		}
+	}
+}
+
+// nextViolation returns the first violation found scanning from the given position
+func (o *codonOptimizer) nextViolation(from int) (violation, bool) {
+	var first violation
+	found := false
+	o.scan(from, func(v violation) bool {
+		first, found = v, true
+		return false
+	})
+
+	return first, found
+}
+
+// unsatisfiable returns an error listing the violated constraints, overlapping GC windows are merged
+func (o *codonOptimizer) unsatisfiable(iterations int) error {
+	var violations []violation
+	o.scan(0, func(v violation) bool {
+		if last := len(violations) - 1; v.constraint == "GC content" {
+			for ; last >= 0; last-- {
+				if violations[last].constraint == v.constraint {
+					break
+				}
+			}
+			if last >= 0 && violations[last].end >= v.start {
+				violations[last].end = v.end
+				return true
+			}
+		}
+		violations = append(violations, v)
+		return true
+	})
+
+	listed := make([]string, 0, maxReportedViolations+1)
+	for _, v := range violations {
+		if len(listed) == maxReportedViolations {
+			listed = append(listed, fmt.Sprintf("and %d more", len(violations)-maxReportedViolations))
+			break
+		}
+		listed = append(listed, v.String())
+	}
+
+	return fmt.Errorf("%w after %d replacements: %s", ErrUnsatisfiableConstraints, iterations, strings.Join(listed, ", "))
+}
+
+func gcCount(seq []byte) int {
+	count := 0
+	for _, base := range seq {
+		if base == 'G' || base == 'C' {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,158 @@
+package sequence
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testHostUsage prefers GC-rich codons, like the codon usage of a GC-rich host
+func testHostUsage(codonTable *CodonTable) CodonUsage {
+	usage := make(CodonUsage)
+	for _, codon := range unambiguousCodons(codonTable) {
+		usage[codon] = 1 + float64(strings.Count(codon, "G")+strings.Count(codon, "C"))
+	}
+	return usage
+}
+
+func TestProteinSequence_OptimizeCodons(t *testing.T) {
+	standardTable, err := GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("GetCodonTable() error = %v", err)
+	}
+	usage := testHostUsage(&standardTable)
+
+	protein := ProteinSequence("MEFKKKKGAPRGGGSNEFLLWQDEFAIVK*")
+
+	tests := []struct {
+		name    string
+		options CodonOptimizationOptions
+	}{
+		{name: "no-constraints"},
+		{name: "restriction-sites", options: CodonOptimizationOptions{AvoidSites: []string{"GAATTC", "GGNCC", "CAGCTG"}}},
+		{name: "homopolymers", options: CodonOptimizationOptions{MaxHomopolymer: 3}},
+		{name: "gc-window", options: CodonOptimizationOptions{GCWindow: 20, MinGC: 0.3, MaxGC: 0.65}},
+		{
+			name: "all",
+			options: CodonOptimizationOptions{
+				AvoidSites:     []string{"GAATTC", "GGNCC"},
+				MaxHomopolymer: 3,
+				GCWindow:       20,
+				MinGC:          0.3,
+				MaxGC:          0.65,
+				MinCodonWeight: 0.3,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dna, err := protein.OptimizeCodons(&standardTable, usage, tt.options)
+			if err != nil {
+				t.Fatalf("OptimizeCodons() error = %v", err)
+			}
+
+			translated, err := dna.TranscribeCoding().Translate(&standardTable)
+			if err != nil || translated != protein {
+				t.Fatalf("optimized DNA %s translates to %s (%v), expected %s", dna, translated, err, protein)
+			}
+
+			// check the result with the constraints of a fresh optimizer
+			optimizer, err := newCodonOptimizer(protein, &standardTable, usage, tt.options)
+			if err != nil {
+				t.Fatalf("newCodonOptimizer() error = %v", err)
+			}
+			optimizer.seq = []byte(dna)
+			if v, violated := optimizer.nextViolation(0); violated {
+				t.Errorf("%s in %s", v, dna)
+			}
+
+			if tt.name == "no-constraints" {
+				expected, _ := protein.BackTranslateWithUsage(&standardTable, usage)
+				if dna != expected {
+					t.Errorf("OptimizeCodons() = %s, expected the most used codons %s", dna, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestProteinSequence_OptimizeCodonsErrors(t *testing.T) {
+	standardTable, err := GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("GetCodonTable() error = %v", err)
+	}
+	usage := testHostUsage(&standardTable)
+
+	unsatisfiable := []struct {
+		name     string
+		protein  ProteinSequence
+		options  CodonOptimizationOptions
+		expected string
+	}{
+		{
+			name:     "site-in-only-codon",
+			protein:  "MKW",
+			options:  CodonOptimizationOptions{AvoidSites: []string{"ATG"}},
+			expected: "site ATG at 1-3",
+		},
+		{
+			name:     "gc-windows-merged",
+			protein:  "MMMMW",
+			options:  CodonOptimizationOptions{GCWindow: 6, MinGC: 0.6},
+			expected: "GC content at 1-15",
+		},
+		{
+			name:     "max-iterations",
+			protein:  "MEFKKKKGAPRGGGSNEFLLWQDEFAIVK*",
+			options:  CodonOptimizationOptions{AvoidSites: []string{"GAATTC"}, MaxHomopolymer: 3, MaxIterations: 1},
+			expected: "after 1 replacements: homopolymer longer than 3 at",
+		},
+	}
+	for _, tt := range unsatisfiable {
+		t.Run(tt.name, func(t *testing.T) {
+			dna, err := tt.protein.OptimizeCodons(&standardTable, usage, tt.options)
+			if !errors.Is(err, ErrUnsatisfiableConstraints) || dna != "" {
+				t.Fatalf("expected ErrUnsatisfiableConstraints and no sequence, got %q, %v", dna, err)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected %q in the error, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	_, err = ProteinSequence("MKW").OptimizeCodons(&standardTable, usage, CodonOptimizationOptions{AvoidSites: []string{"GAXTTC"}})
+	var invalid *InvalidCharacterError
+	if !errors.As(err, &invalid) {
+		t.Errorf("expected InvalidCharacterError, got %v", err)
+	}
+
+	_, err = ProteinSequence("M-K").OptimizeCodons(&standardTable, usage, CodonOptimizationOptions{})
+	if !errors.Is(err, ErrNoCodonForResidue) {
+		t.Errorf("expected ErrNoCodonForResidue, got %v", err)
+	}
+}
+
+func BenchmarkProteinSequence_OptimizeCodons(b *testing.B) {
+	standardTable, _ := GetCodonTable(1)
+	usage := testHostUsage(&standardTable)
+	protein, err := syntheticGenome(30_000).TranscribeCoding().Translate(&standardTable)
+	if err != nil {
+		b.Fatal(err)
+	}
+	protein = ProteinSequence(strings.ReplaceAll(string(protein), "*", ""))
+	options := CodonOptimizationOptions{
+		AvoidSites:     []string{"GAATTC", "GGATCC", "GGTCTC"},
+		MaxHomopolymer: 4,
+		GCWindow:       50,
+		MinGC:          0.3,
+		MaxGC:          0.65,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := protein.OptimizeCodons(&standardTable, usage, options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package sequence
+
+import (
+	"strings"
+)
+
+// CodonCounts maps uppercase RNA codons to their number of occurrences.
+type CodonCounts map[string]int
+
+// CountCodons counts the in-frame codons of a coding sequence read from its first base.
+// Codons with ambiguous bases and a trailing partial codon are skipped.
+func CountCodons(cds RNASequence) CodonCounts {
+	counts := make(CodonCounts)
+	seq := strings.ToUpper(string(cds))
+
+	for i := 0; i+3 <= len(seq); i += 3 {
+		codon := seq[i : i+3]
+		if strings.Trim(codon, rnaBases) == "" {
+			counts[codon]++
+		}
+	}
+
+	return counts
+}
+
+func (c CodonCounts) Add(other CodonCounts) {
+	for codon, count := range other {
+		c[codon] += count
+	}
+}
+
+func (c CodonCounts) Total() int {
+	total := 0
+	for _, count := range c {
+		total += count
+	}
+	return total
+}
+
+type GeneCodonCounts struct {
+	ID     string
+	Counts CodonCounts
+}
+
+// CodonUsageTable collects codon counts of the coding sequences of a genome. Codons are assigned
+// to amino acids by CodonTable.
+type CodonUsageTable struct {
+	CodonTable *CodonTable
+	// Genes are the per-gene counts in the order they were added, empty for tables loaded from a file.
+	Genes  []GeneCodonCounts
+	Counts CodonCounts
+}
+
+func NewCodonUsageTable(codonTable *CodonTable) *CodonUsageTable {
+	return &CodonUsageTable{
+		CodonTable: codonTable,
+		Counts:     make(CodonCounts),
+	}
+}
+
+// AddGene counts the codons of an in-frame coding sequence, e.g. an ORF or a CDS feature.
+func (t *CodonUsageTable) AddGene(id string, cds RNASequence) CodonCounts {
+	counts := CountCodons(cds)
+	t.Genes = append(t.Genes, GeneCodonCounts{ID: id, Counts: counts})
+	t.Counts.Add(counts)
+
+	return counts
+}
+
+// PerThousand returns the frequency of every codon of the codon table per 1000 codons.
+func (t *CodonUsageTable) PerThousand() CodonUsage {
+	usage := make(CodonUsage)
+	total := t.Counts.Total()
+
+	for _, codon := range unambiguousCodons(t.CodonTable) {
+		usage[codon] = 0
+		if total > 0 {
+			usage[codon] = float64(t.Counts[codon]) * 1000 / float64(total)
+		}
+	}
+
+	return usage
+}
+
+// Fractions returns the frequency of every codon among the synonymous codons of its amino acid.
+// Codons of amino acids that were never seen have fraction 0.
+func (t *CodonUsageTable) Fractions() CodonUsage {
+	usage := make(CodonUsage)
+
+	for _, codons := range t.synonymousFamilies() {
+		total := 0
+		for _, codon := range codons {
+			total += t.Counts[codon]
+		}
+
+		for _, codon := range codons {
+			usage[codon] = 0
+			if total > 0 {
+				usage[codon] = float64(t.Counts[codon]) / float64(total)
+			}
+		}
+	}
+
+	return usage
+}
+
+// RSCU returns the relative synonymous codon usage: the number of occurrences of a codon divided by the mean
+// number of occurrences of the synonymous codons. RSCU is 1 for codons used as often as expected under uniform
+// usage. Codons of amino acids that were never seen have RSCU 0.
+func (t *CodonUsageTable) RSCU() CodonUsage {
+	rscu := t.Fractions()
+	for _, codons := range t.synonymousFamilies() {
+		for _, codon := range codons {
+			rscu[codon] *= float64(len(codons))
+		}
+	}
+
+	return rscu
+}
+
+// AminoAcidComposition returns the fraction of every amino acid among the sense codons, stops are excluded.
+func (t *CodonUsageTable) AminoAcidComposition() map[AminoAcid]float64 {
+	composition := make(map[AminoAcid]float64)
+
+	total := 0
+	for aa, codons := range t.synonymousFamilies() {
+		if aa == '*' {
+			continue
+		}
+		for _, codon := range codons {
+			composition[aa] += float64(t.Counts[codon])
+			total += t.Counts[codon]
+		}
+	}
+
+	if total > 0 {
+		for aa := range composition {
+			composition[aa] /= float64(total)
+		}
+	}
+
+	return composition
+}
+
+// synonymousFamilies groups the codons of the codon table by the amino acid they encode
+func (t *CodonUsageTable) synonymousFamilies() map[AminoAcid][]string {
+	return synonymousFamilies(t.CodonTable)
+}
+
+// unambiguousCodons returns the unambiguous codons of the codon table in the genetic code table order
+func unambiguousCodons(codonTable *CodonTable) []string {
+	var codons []string
+	for _, codon := range codonsInTableOrder(codonTable.Codons) {
+		if strings.Trim(codon, rnaBases) == "" {
+			codons = append(codons, codon)
+		}
+	}
+
+	return codons
+}
+
+func synonymousFamilies(codonTable *CodonTable) map[AminoAcid][]string {
+	families := make(map[AminoAcid][]string)
+	for _, codon := range unambiguousCodons(codonTable) {
+		aa := codonTable.Codons[codon]
+		families[aa] = append(families[aa], codon)
+	}
+
+	return families
+}
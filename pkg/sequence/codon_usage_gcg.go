@@ -0,0 +1,101 @@
+package sequence
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// gcgBases is the order of bases in GCG codon frequency tables
+const gcgBases = "GATC"
+
+const gcgHeader = "AmAcid  Codon      Number    /1000     Fraction   ..\n\n"
+
+var threeLetterCodes = map[AminoAcid]string{
+	'A': "Ala", 'R': "Arg", 'N': "Asn", 'D': "Asp", 'C': "Cys",
+	'Q': "Gln", 'E': "Glu", 'G': "Gly", 'H': "His", 'I': "Ile",
+	'L': "Leu", 'K': "Lys", 'M': "Met", 'F': "Phe", 'P': "Pro",
+	'S': "Ser", 'T': "Thr", 'W': "Trp", 'Y': "Tyr", 'V': "Val",
+	'B': "Asx", 'Z': "Glx", 'J': "Xle", 'X': "Xaa", 'U': "Sec", 'O': "Pyl",
+	'*': "End",
+}
+
+// WriteGCG writes the table in the GCG codon frequency format used by the Kazusa codon usage database:
+// amino acid, DNA codon, number of occurrences, frequency per 1000 codons and fraction among synonymous codons.
+func (t *CodonUsageTable) WriteGCG(writer io.Writer) error {
+	_, err := io.WriteString(writer, gcgHeader)
+	if err != nil {
+		return err
+	}
+
+	perThousand := t.PerThousand()
+	fractions := t.Fractions()
+
+	for _, first := range gcgBases {
+		for _, second := range gcgBases {
+			for _, third := range gcgBases {
+				codon := strings.ReplaceAll(string([]rune{first, second, third}), "T", "U")
+				aa, ok := t.CodonTable.Codons[codon]
+				if !ok {
+					continue
+				}
+
+				name, ok := threeLetterCodes[aa]
+				if !ok {
+					name = string(aa)
+				}
+
+				_, err = fmt.Fprintf(writer, "%-7s %-5s %11.2f %9.2f %13.2f\n", name, strings.ReplaceAll(codon, "U", "T"),
+					float64(t.Counts[codon]), perThousand[codon], fractions[codon])
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = io.WriteString(writer, "\n")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ParseGCGCodonUsage reads codon counts in the GCG codon frequency format, lines that are not codon
+// entries (headers, comments) are skipped. Only the number of occurrences is used, the other columns
+// are recomputed from it.
+func ParseGCGCodonUsage(reader io.Reader, codonTable *CodonTable) (*CodonUsageTable, error) {
+	table := NewCodonUsageTable(codonTable)
+	scanner := bufio.NewScanner(reader)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || len(fields[1]) != 3 || fields[0] == "AmAcid" {
+			continue
+		}
+
+		codon := strings.ReplaceAll(strings.ToUpper(fields[1]), "T", "U")
+		if strings.Trim(codon, rnaBases) != "" {
+			continue
+		}
+
+		number, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("invalid number of codons %q on line %d", fields[2], lineNumber)
+		}
+
+		table.Counts[codon] += int(math.Round(number))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
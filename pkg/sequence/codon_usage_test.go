@@ -0,0 +1,115 @@
+package sequence
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountCodons(t *testing.T) {
+	got := CountCodons("AUGaaaAAGNNNAAAUAAGG")
+	expected := CodonCounts{"AUG": 1, "AAA": 2, "AAG": 1, "UAA": 1}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("CountCodons() = %v, expected %v", got, expected)
+	}
+	if got.Total() != 5 {
+		t.Errorf("Total() = %d, expected 5", got.Total())
+	}
+}
+
+func newTestCodonUsageTable(t *testing.T) *CodonUsageTable {
+	t.Helper()
+
+	standardTable, err := GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("GetCodonTable() error = %v", err)
+	}
+
+	table := NewCodonUsageTable(&standardTable)
+	table.AddGene("gene1", "AUGAAAAAAAAGCUGUAA")
+	table.AddGene("gene2", "AUGCUGCUGUUAUGA")
+
+	return table
+}
+
+func TestCodonUsageTable(t *testing.T) {
+	table := newTestCodonUsageTable(t)
+
+	if len(table.Genes) != 2 || table.Genes[1].ID != "gene2" || table.Genes[1].Counts["CUG"] != 2 {
+		t.Errorf("unexpected per-gene counts %+v", table.Genes)
+	}
+	if table.Counts["CUG"] != 3 || table.Counts.Total() != 11 {
+		t.Errorf("unexpected counts %v", table.Counts)
+	}
+
+	rscu := table.RSCU()
+	for codon, expected := range map[string]float64{
+		"AUG": 1,          // single codon family
+		"AAA": 2 * 2 / 3., // 2 of 3 Lys codons, 2 synonymous codons
+		"AAG": 2 * 1 / 3.,
+		"CUG": 6 * 3 / 4., // 3 of 4 Leu codons, 6 synonymous codons
+		"UUA": 6 * 1 / 4.,
+		"CUU": 0,
+		"UAA": 3 * 1 / 2., // 1 of 2 stops, 3 stop codons
+		"GGG": 0,          // unseen amino acid
+	} {
+		if math.Abs(rscu[codon]-expected) > 1e-9 {
+			t.Errorf("RSCU[%s] = %v, expected %v", codon, rscu[codon], expected)
+		}
+	}
+
+	if perThousand := table.PerThousand(); math.Abs(perThousand["CUG"]-3000/11.) > 1e-9 || len(perThousand) != 64 {
+		t.Errorf("unexpected per thousand frequencies %v", perThousand)
+	}
+
+	composition := table.AminoAcidComposition()
+	expectedComposition := map[AminoAcid]float64{'M': 2 / 9., 'K': 3 / 9., 'L': 4 / 9.}
+	for aa, fraction := range composition {
+		if math.Abs(fraction-expectedComposition[aa]) > 1e-9 {
+			t.Errorf("composition[%c] = %v, expected %v", aa, fraction, expectedComposition[aa])
+		}
+	}
+}
+
+func TestCodonUsageTable_GCG(t *testing.T) {
+	table := newTestCodonUsageTable(t)
+
+	var buf bytes.Buffer
+	if err := table.WriteGCG(&buf); err != nil {
+		t.Fatalf("WriteGCG() error = %v", err)
+	}
+
+	written := buf.String()
+	for _, line := range []string{
+		"AmAcid  Codon      Number    /1000     Fraction   ..",
+		"Gly     GGG          0.00      0.00          0.00",
+		"Leu     CTG          3.00    272.73          0.75",
+		"End     TAA          1.00     90.91          0.50",
+	} {
+		if !strings.Contains(written, line+"\n") {
+			t.Errorf("expected line %q in\n%s", line, written)
+		}
+	}
+	if !strings.HasPrefix(written[len(gcgHeader):], "Gly     GGG") {
+		t.Errorf("expected GCG codon order starting with GGG")
+	}
+
+	parsed, err := ParseGCGCodonUsage(strings.NewReader(written), table.CodonTable)
+	if err != nil {
+		t.Fatalf("ParseGCGCodonUsage() error = %v", err)
+	}
+	expected := CodonCounts{"AUG": 2, "AAA": 2, "AAG": 1, "CUG": 3, "UUA": 1, "UAA": 1, "UGA": 1}
+	for codon, count := range parsed.Counts {
+		if count != expected[codon] {
+			t.Errorf("parsed count of %s = %d, expected %d", codon, count, expected[codon])
+		}
+	}
+
+	_, err = ParseGCGCodonUsage(strings.NewReader("Gly     GGG     many     16.45      0.22\n"), table.CodonTable)
+	if err == nil {
+		t.Errorf("expected error for invalid number")
+	}
+}
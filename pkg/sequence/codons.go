@@ -2,6 +2,7 @@ package sequence
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -14,33 +15,43 @@ type CodonTable struct {
 	StopCodons  map[string]AminoAcid
 }
 
-func GetCodonTable(id int) (CodonTable, error) {
-	for _, c := range codonTables {
-		if id == c.ID {
-			return c.Copy(), nil
-		}
-	}
+func (c *CodonTable) Copy() CodonTable {
+	tableCopy := *c
+	tableCopy.Codons = copyCodons(c.Codons)
+	tableCopy.StartCodons = copyCodons(c.StartCodons)
+	tableCopy.StopCodons = copyCodons(c.StopCodons)
 
-	return CodonTable{}, fmt.Errorf("codon table no. %d not found", id)
+	return tableCopy
 }
 
-func CodonTables() []CodonTable {
-	return codonTables
+func copyCodons(codons map[string]AminoAcid) map[string]AminoAcid {
+	if codons == nil {
+		return nil
+	}
+
+	copied := make(map[string]AminoAcid, len(codons))
+	for k, v := range codons {
+		copied[k] = v
+	}
+
+	return copied
 }
 
-func (c *CodonTable) Copy() CodonTable {
-	tableCopy := *c
+func (c *CodonTable) ModifyCodonUsage(customCodons map[string]AminoAcid) error {
+	err := validateCodonUsage(customCodons, false)
+	if err != nil {
+		return err
+	}
 
-	copiedCodons := make(map[string]AminoAcid, len(tableCopy.Codons))
-	for k, v := range tableCopy.Codons {
-		copiedCodons[k] = v
+	// Modify the codon table
+	for codon, aa := range customCodons {
+		c.Codons[codon] = aa
 	}
 
-	tableCopy.Codons = copiedCodons
-	return tableCopy
+	return nil
 }
 
-func (c *CodonTable) ModifyCodonUsage(customCodons map[string]AminoAcid) error {
+func validateCodonUsage(customCodons map[string]AminoAcid, allowStop bool) error {
 	for codon, aa := range customCodons {
 		// Check if the codon is 3 characters long
 		if len(codon) != 3 {
@@ -55,16 +66,11 @@ func (c *CodonTable) ModifyCodonUsage(customCodons map[string]AminoAcid) error {
 		}
 
 		// Validate the amino acid
-		if !isValidAminoAcid(aa) {
+		if !isValidAminoAcid(aa) && !(allowStop && aa == '*') {
 			return fmt.Errorf("invalid amino acid '%c' for codon '%s'", aa, codon)
 		}
 	}
 
-	// Modify the codon table
-	for codon, aa := range customCodons {
-		c.Codons[codon] = aa
-	}
-
 	return nil
 }
 
@@ -92,11 +98,19 @@ func isValidAminoAcid(aa AminoAcid) bool {
 	return isBasic || isAmbiguous
 }
 
+// TranslateCodon returns the amino acid encoded by codon. Ambiguous codons give the most specific IUPAC code
+// of their possible residues: a single residue, B (N or D), Z (Q or E), J (L or I) or X otherwise.
+// Codons the table doesn't know give X.
 func (c *CodonTable) TranslateCodon(codon string) AminoAcid {
+	return mostSpecificAminoAcid(c.PossibleAminoAcids(codon))
+}
+
+// PossibleAminoAcids returns the sorted residues encoded by any expansion of an ambiguous codon.
+// Stop codons give '*', nil is returned when an expansion is missing from the table.
+func (c *CodonTable) PossibleAminoAcids(codon string) []AminoAcid {
 	possibleBases := make([][]Nucleotide, len(codon))
 
 	codon = strings.ToUpper(codon)
-	codonsCount := 1
 
 	for i, b := range codon {
 		base := Nucleotide(b)
@@ -104,7 +118,6 @@ func (c *CodonTable) TranslateCodon(codon string) AminoAcid {
 		variants, isAmbiguous := AmbiguousNucleotidesMap[base]
 		if isAmbiguous {
 			possibleBases[i] = variants
-			codonsCount = codonsCount * len(variants)
 		} else {
 			possibleBases[i] = []Nucleotide{base}
 		}
@@ -113,16 +126,63 @@ func (c *CodonTable) TranslateCodon(codon string) AminoAcid {
 	possibleCodons := make([]string, 0)
 	allCombinations(possibleBases, 0, make([]Nucleotide, len(possibleBases)), &possibleCodons)
 
-	var result AminoAcid
+	var residues []AminoAcid
 	for _, codon := range possibleCodons {
 		aa, found := c.Codons[codon]
-		// codon not found or differs from previous
-		if !found || (result != 0 && aa != result) {
-			return 'X'
+		if !found {
+			return nil
+		}
+
+		// codon tables may assign ambiguous amino acids themselves
+		expanded, isAmbiguous := AmbiguousAminoAcidsMap[aa]
+		if !isAmbiguous {
+			expanded = []AminoAcid{aa}
+		}
+		for _, residue := range expanded {
+			if !containsAminoAcid(residues, residue) {
+				residues = append(residues, residue)
+			}
+		}
+	}
+
+	sort.Slice(residues, func(i, j int) bool { return residues[i] < residues[j] })
+	return residues
+}
+
+// ambiguousAminoAcidCodes are tried from the most specific one
+var ambiguousAminoAcidCodes = []AminoAcid{'B', 'Z', 'J'}
+
+func mostSpecificAminoAcid(residues []AminoAcid) AminoAcid {
+	switch len(residues) {
+	case 0:
+		return 'X'
+	case 1:
+		return residues[0]
+	}
+
+	for _, code := range ambiguousAminoAcidCodes {
+		covered := true
+		for _, residue := range residues {
+			if !containsAminoAcid(AmbiguousAminoAcidsMap[code], residue) {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return code
+		}
+	}
+
+	return 'X'
+}
+
+func containsAminoAcid(aminoAcids []AminoAcid, aa AminoAcid) bool {
+	for _, a := range aminoAcids {
+		if a == aa {
+			return true
 		}
-		result = aa
 	}
-	return result
+	return false
 }
 
 func allCombinations(bases [][]Nucleotide, index int, currCombination []Nucleotide, result *[]string) {
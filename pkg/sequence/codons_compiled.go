@@ -0,0 +1,130 @@
+package sequence
+
+import "strings"
+
+// compiledCodons is the number of codons of IUPAC RNA letters, each letter is a 4-bit mask
+const compiledCodons = 16 * 16 * 16
+
+// CompiledCodonTable is an immutable, array-indexed form of a CodonTable with precomputed translations
+// of all codons of IUPAC RNA letters. It is safe for concurrent use and translates without allocations.
+// Compile the table again after modifying the CodonTable it was compiled from.
+type CompiledCodonTable struct {
+	ID          int
+	aminoAcids  [compiledCodons]AminoAcid
+	startCodons [compiledCodons]bool
+	stopCodons  [compiledCodons]bool
+}
+
+// rnaLetterMasks map RNA letters of either case to their IUPAC mask (A=1, C=2, G=4, U=8), 0 for other letters
+var rnaLetterMasks = func() [256]byte {
+	var masks [256]byte
+	for mask, letter := range []byte(rnaMaskLetters) {
+		if letter != '-' {
+			masks[letter] = byte(mask)
+			masks[letter+'a'-'A'] = byte(mask)
+		}
+	}
+	return masks
+}()
+
+// compiledIndex returns the index of a codon in the compiled arrays, false for codons with other letters
+func compiledIndex(codon string) (int, bool) {
+	if len(codon) != 3 {
+		return 0, false
+	}
+
+	first, second, third := rnaLetterMasks[codon[0]], rnaLetterMasks[codon[1]], rnaLetterMasks[codon[2]]
+	if first == 0 || second == 0 || third == 0 {
+		return 0, false
+	}
+
+	return int(first)<<8 | int(second)<<4 | int(third), true
+}
+
+// Compile precomputes TranslateCodon for every codon of IUPAC RNA letters.
+func (c *CodonTable) Compile() *CompiledCodonTable {
+	compiled := &CompiledCodonTable{ID: c.ID}
+
+	for i := range compiled.aminoAcids {
+		compiled.aminoAcids[i] = 'X'
+	}
+
+	codon := make([]byte, 3)
+	for first := 1; first < 16; first++ {
+		for second := 1; second < 16; second++ {
+			for third := 1; third < 16; third++ {
+				codon[0], codon[1], codon[2] = rnaMaskLetters[first], rnaMaskLetters[second], rnaMaskLetters[third]
+				index := first<<8 | second<<4 | third
+
+				compiled.aminoAcids[index] = c.TranslateCodon(string(codon))
+			}
+		}
+	}
+
+	for codon := range c.StartCodons {
+		if index, ok := compiledIndex(codon); ok {
+			compiled.startCodons[index] = true
+		}
+	}
+	for codon := range c.StopCodons {
+		if index, ok := compiledIndex(codon); ok {
+			compiled.stopCodons[index] = true
+		}
+	}
+
+	return compiled
+}
+
+// TranslateCodon gives the same result as CodonTable.TranslateCodon, codons with letters other than
+// IUPAC RNA letters give X.
+func (t *CompiledCodonTable) TranslateCodon(codon string) AminoAcid {
+	index, ok := compiledIndex(codon)
+	if !ok {
+		return 'X'
+	}
+
+	return t.aminoAcids[index]
+}
+
+func (t *CompiledCodonTable) translateCodon(codon string) AminoAcid {
+	return t.TranslateCodon(codon)
+}
+
+func (t *CompiledCodonTable) isStartCodon(codon string) bool {
+	index, ok := compiledIndex(codon)
+	return ok && t.startCodons[index]
+}
+
+func (t *CompiledCodonTable) isStopCodon(codon string) bool {
+	index, ok := compiledIndex(codon)
+	return ok && (t.stopCodons[index] || t.aminoAcids[index] == '*')
+}
+
+// Translate translates r like RNASequence.Translate, ignoring a trailing partial codon.
+func (t *CompiledCodonTable) Translate(r RNASequence) (ProteinSequence, error) {
+	return t.TranslateWithOptions(r, TranslationOptions{TrimPartialCodon: true})
+}
+
+func (t *CompiledCodonTable) TranslateWithOptions(r RNASequence, options TranslationOptions) (ProteinSequence, error) {
+	var sb strings.Builder
+	sb.Grow(len(r) / 3)
+
+	err := translate(r, t, options, func(aa AminoAcid) {
+		sb.WriteByte(byte(aa))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return ProteinSequence(sb.String()), nil
+}
+
+// AppendTranslation appends the translation of r to dst and returns the extended slice. Reusing dst
+// for a set of sequences translates them without allocations.
+func (t *CompiledCodonTable) AppendTranslation(dst []AminoAcid, r RNASequence, options TranslationOptions) ([]AminoAcid, error) {
+	err := translate(r, t, options, func(aa AminoAcid) {
+		dst = append(dst, aa)
+	})
+
+	return dst, err
+}
@@ -0,0 +1,106 @@
+package sequence
+
+import (
+	"testing"
+)
+
+func TestCompiledCodonTable_TranslateCodon(t *testing.T) {
+	letters := "ACGUTRYSWKMBDHVNa-"
+
+	for _, id := range []int{1, 2, 11} {
+		table, err := GetCodonTable(id)
+		if err != nil {
+			t.Fatalf("GetCodonTable() error = %v", err)
+		}
+		compiled := table.Compile()
+
+		for _, first := range letters {
+			for _, second := range letters {
+				for _, third := range letters {
+					codon := string([]rune{first, second, third})
+					if got, expected := compiled.TranslateCodon(codon), table.TranslateCodon(codon); got != expected {
+						t.Errorf("table %d: TranslateCodon(%s) = %c, expected %c", id, codon, got, expected)
+					}
+				}
+			}
+		}
+
+		if got := compiled.TranslateCodon("AUGA"); got != 'X' {
+			t.Errorf("expected X for codon of wrong length, got %c", got)
+		}
+	}
+}
+
+func TestCompiledCodonTable_TranslateWithOptions(t *testing.T) {
+	table, _ := GetCodonTable(11)
+	compiled := table.Compile()
+
+	for _, tc := range []struct {
+		rna     RNASequence
+		options TranslationOptions
+	}{
+		{"AUGCCCUAAGG", TranslationOptions{TrimPartialCodon: true}},
+		{"GUGCCCUAA", TranslationOptions{CDS: true}},
+		{"AUGUAACCCUAA", TranslationOptions{CDS: true}},
+		{"AUGCCCRAYUAAGGG", TranslationOptions{ToStop: true}},
+		{"AUGCC", TranslationOptions{}},
+	} {
+		expected, expectedErr := tc.rna.TranslateWithOptions(&table, tc.options)
+		got, err := compiled.TranslateWithOptions(tc.rna, tc.options)
+		if got != expected || (err == nil) != (expectedErr == nil) {
+			t.Errorf("TranslateWithOptions(%s) = %s, %v, expected %s, %v", tc.rna, got, err, expected, expectedErr)
+		}
+	}
+}
+
+func TestCompiledCodonTable_AppendTranslation_Allocations(t *testing.T) {
+	table, _ := GetCodonTable(1)
+	compiled := table.Compile()
+	genes := []RNASequence{"AUGCCCUAA", "AUGRAYNNNUGA", "augcccuaa"}
+
+	buf := make([]AminoAcid, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, gene := range genes {
+			var err error
+			buf, err = compiled.AppendTranslation(buf[:0], gene, TranslationOptions{TrimPartialCodon: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected translation without allocations, got %v per run", allocs)
+	}
+	if string(buf) != "MP*" {
+		t.Errorf("unexpected translation %s", string(buf))
+	}
+}
+
+func BenchmarkCompiledCodonTable_AppendTranslation(b *testing.B) {
+	genes := syntheticGenes(1000)
+	standardTable, _ := GetCodonTable(1)
+	compiled := standardTable.Compile()
+	options := TranslationOptions{TrimPartialCodon: true}
+
+	var buf []AminoAcid
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, gene := range genes {
+			var err error
+			buf, err = compiled.AppendTranslation(buf[:0], gene, options)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkCodonTable_Compile(b *testing.B) {
+	standardTable, _ := GetCodonTable(1)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		standardTable.Compile()
+	}
+}
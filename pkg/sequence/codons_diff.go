@@ -0,0 +1,190 @@
+package sequence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// rnaBases is the conventional order of bases in genetic code tables.
+const rnaBases = "UCAG"
+
+type CodonReassignment struct {
+	Codon string
+	// From and To are zero when the codon is missing from the corresponding table.
+	From AminoAcid
+	To   AminoAcid
+}
+
+// CodonTableDiff lists the changes needed to turn one codon table into another.
+type CodonTableDiff struct {
+	FromID        int
+	ToID          int
+	Reassignments []CodonReassignment
+	StartsGained  []string
+	StartsLost    []string
+	StopsGained   []string
+	StopsLost     []string
+}
+
+// Diff compares c with other. Codons are reported in the standard genetic code table order.
+func (c *CodonTable) Diff(other *CodonTable) CodonTableDiff {
+	diff := CodonTableDiff{
+		FromID: c.ID,
+		ToID:   other.ID,
+	}
+
+	for _, codon := range codonsInTableOrder(c.Codons, other.Codons) {
+		from, to := c.Codons[codon], other.Codons[codon]
+		if from != to {
+			diff.Reassignments = append(diff.Reassignments, CodonReassignment{Codon: codon, From: from, To: to})
+		}
+	}
+
+	diff.StartsGained, diff.StartsLost = compareCodonSets(c.StartCodons, other.StartCodons)
+	diff.StopsGained, diff.StopsLost = compareCodonSets(c.StopCodons, other.StopCodons)
+
+	return diff
+}
+
+func (d CodonTableDiff) Empty() bool {
+	return len(d.Reassignments) == 0 && len(d.StartsGained) == 0 && len(d.StartsLost) == 0 &&
+		len(d.StopsGained) == 0 && len(d.StopsLost) == 0
+}
+
+func (d CodonTableDiff) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Codon table no. %d -> no. %d\n", d.FromID, d.ToID)
+
+	if d.Empty() {
+		sb.WriteString("  no differences\n")
+		return sb.String()
+	}
+
+	if len(d.Reassignments) > 0 {
+		sb.WriteString("  Reassigned codons:\n")
+		for _, r := range d.Reassignments {
+			fmt.Fprintf(&sb, "    %s: %s -> %s\n", r.Codon, aminoAcidLabel(r.From), aminoAcidLabel(r.To))
+		}
+	}
+
+	for _, group := range []struct {
+		title  string
+		codons []string
+	}{
+		{"Start codons gained", d.StartsGained},
+		{"Start codons lost", d.StartsLost},
+		{"Stop codons gained", d.StopsGained},
+		{"Stop codons lost", d.StopsLost},
+	} {
+		if len(group.codons) > 0 {
+			fmt.Fprintf(&sb, "  %s: %s\n", group.title, strings.Join(group.codons, " "))
+		}
+	}
+
+	return sb.String()
+}
+
+// Grid renders the table in the standard 4x16 layout: the first base selects the block of rows,
+// the second base the column and the third base the row inside a block.
+// Start codons are marked with "i", as in the NCBI genetic code pages.
+func (c *CodonTable) Grid() string {
+	var sb strings.Builder
+
+	header := "  "
+	for _, second := range rnaBases {
+		header += fmt.Sprintf("   %c      ", second)
+	}
+	sb.WriteString(strings.TrimRight(header, " "))
+	sb.WriteString("\n")
+
+	for i, first := range rnaBases {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		for j, third := range rnaBases {
+			if j == 0 {
+				fmt.Fprintf(&sb, "%c ", first)
+			} else {
+				sb.WriteString("  ")
+			}
+
+			for _, second := range rnaBases {
+				codon := string([]rune{first, second, third})
+
+				marker := ' '
+				if _, isStart := c.StartCodons[codon]; isStart {
+					marker = 'i'
+				}
+
+				fmt.Fprintf(&sb, " %s %s %c  ", codon, aminoAcidLabel(c.Codons[codon]), marker)
+			}
+
+			fmt.Fprintf(&sb, "%c\n", third)
+		}
+	}
+
+	return sb.String()
+}
+
+func aminoAcidLabel(aa AminoAcid) string {
+	if aa == 0 {
+		return "-"
+	}
+
+	return string(aa)
+}
+
+// codonsInTableOrder returns the codons present in any of the maps, standard codons first
+// in genetic code table order, then any other (e.g. ambiguous) codons sorted alphabetically.
+func codonsInTableOrder(maps ...map[string]AminoAcid) []string {
+	codons := make([]string, 0, 64)
+	standard := make(map[string]bool, 64)
+
+	for _, first := range rnaBases {
+		for _, second := range rnaBases {
+			for _, third := range rnaBases {
+				codon := string([]rune{first, second, third})
+				standard[codon] = true
+
+				for _, m := range maps {
+					if _, ok := m[codon]; ok {
+						codons = append(codons, codon)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	var extra []string
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		for codon := range m {
+			if !standard[codon] && !seen[codon] {
+				seen[codon] = true
+				extra = append(extra, codon)
+			}
+		}
+	}
+	sort.Strings(extra)
+
+	return append(codons, extra...)
+}
+
+func compareCodonSets(from, to map[string]AminoAcid) (gained []string, lost []string) {
+	for _, codon := range codonsInTableOrder(from, to) {
+		_, inFrom := from[codon]
+		_, inTo := to[codon]
+
+		if inTo && !inFrom {
+			gained = append(gained, codon)
+		}
+		if inFrom && !inTo {
+			lost = append(lost, codon)
+		}
+	}
+
+	return gained, lost
+}
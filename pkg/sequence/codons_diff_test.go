@@ -0,0 +1,116 @@
+package sequence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodonTable_Diff(t *testing.T) {
+	standard, _ := GetCodonTable(1)
+	invertebrateMito, _ := GetCodonTable(5)
+
+	modified := standard.Copy()
+	_ = modified.ModifyCodonUsage(map[string]AminoAcid{"CUY": 'L'})
+
+	tests := []struct {
+		name     string
+		from     CodonTable
+		to       CodonTable
+		expected CodonTableDiff
+	}{
+		{
+			name: "standard-vs-invertebrate-mitochondrial",
+			from: standard,
+			to:   invertebrateMito,
+			expected: CodonTableDiff{
+				FromID: 1,
+				ToID:   5,
+				Reassignments: []CodonReassignment{
+					{Codon: "UGA", From: '*', To: 'W'},
+					{Codon: "AUA", From: 'I', To: 'M'},
+					{Codon: "AGA", From: 'R', To: 'S'},
+					{Codon: "AGG", From: 'R', To: 'S'},
+				},
+				StartsGained: []string{"AUU", "AUC", "AUA", "GUG"},
+				StartsLost:   []string{"CUG"},
+				StopsLost:    []string{"UGA"},
+			},
+		},
+		{
+			name:     "identical",
+			from:     standard,
+			to:       standard.Copy(),
+			expected: CodonTableDiff{FromID: 1, ToID: 1},
+		},
+		{
+			name: "codon-missing-in-one-table",
+			from: standard,
+			to:   modified,
+			expected: CodonTableDiff{
+				FromID:        1,
+				ToID:          1,
+				Reassignments: []CodonReassignment{{Codon: "CUY", From: 0, To: 'L'}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.from.Diff(&tt.to)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Diff() got = %+v, expected %+v", got, tt.expected)
+			}
+			if got.Empty() != (tt.name == "identical") {
+				t.Errorf("Empty() = %v for %s", got.Empty(), tt.name)
+			}
+		})
+	}
+}
+
+func TestCodonTableDiff_String(t *testing.T) {
+	standard, _ := GetCodonTable(1)
+	vertebrateMito, _ := GetCodonTable(2)
+
+	expected := `Codon table no. 1 -> no. 2
+  Reassigned codons:
+    UGA: * -> W
+    AUA: I -> M
+    AGA: R -> *
+    AGG: R -> *
+  Start codons gained: AUU AUC AUA GUG
+  Start codons lost: UUG CUG
+  Stop codons gained: AGA AGG
+  Stop codons lost: UGA
+`
+	if got := standard.Diff(&vertebrateMito).String(); got != expected {
+		t.Errorf("String() got:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	if got := standard.Diff(&standard).String(); !strings.Contains(got, "no differences") {
+		t.Errorf("String() of empty diff got %q", got)
+	}
+}
+
+func TestCodonTable_Grid(t *testing.T) {
+	standard, _ := GetCodonTable(1)
+	grid := standard.Grid()
+	lines := strings.Split(strings.TrimRight(grid, "\n"), "\n")
+
+	// header + 4 blocks of 4 rows + 3 separating lines
+	if len(lines) != 20 {
+		t.Fatalf("expected 20 lines, got %d:\n%s", len(lines), grid)
+	}
+
+	expectedLines := map[int]string{
+		0:  "     U         C         A         G",
+		1:  "U  UUU F     UCU S     UAU Y     UGU C    U",
+		4:  "   UUG L i   UCG S     UAG *     UGG W    G",
+		14: "   AUG M i   ACG T     AAG K     AGG R    G",
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("line %d: got %q, expected %q", i, lines[i], expected)
+		}
+	}
+}
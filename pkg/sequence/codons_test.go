@@ -2,6 +2,7 @@ package sequence
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -18,6 +19,11 @@ func TestCodonTable_TranslateCodon(t *testing.T) {
 		{"Ambiguous codon with single amino acid", "CUY", 'L'},
 		{"Ambiguous stop codon", "URA", '*'},
 		{"Ambiguous codon with multiple amino acids", "AAM", 'X'},
+		{"Asparagine or aspartate", "RAY", 'B'},
+		{"Glutamine or glutamate", "SAR", 'Z'},
+		{"Leucine or isoleucine", "MUU", 'J'},
+		{"Leucine or isoleucine with A", "MUA", 'J'},
+		{"Amino acid or stop", "UAN", 'X'},
 		{"Invalid codon", "AU-", 'X'},
 	}
 
@@ -31,6 +37,38 @@ func TestCodonTable_TranslateCodon(t *testing.T) {
 	}
 }
 
+func TestCodonTable_PossibleAminoAcids(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+
+	testCases := []struct {
+		codon    string
+		expected []AminoAcid
+	}{
+		{"AUG", []AminoAcid{'M'}},
+		{"RAY", []AminoAcid{'D', 'N'}},
+		{"UAN", []AminoAcid{'*', 'Y'}},
+		{"NNN", []AminoAcid{'*', 'A', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'K', 'L', 'M', 'N', 'P', 'Q', 'R', 'S', 'T', 'V', 'W', 'Y'}},
+		{"AU-", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.codon, func(t *testing.T) {
+			if got := standardTable.PossibleAminoAcids(tc.codon); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("PossibleAminoAcids(%s) = %q, expected %q", tc.codon, got, tc.expected)
+			}
+		})
+	}
+
+	custom := standardTable.Copy()
+	custom.Codons["AAU"] = 'B'
+	if got := custom.PossibleAminoAcids("AAU"); !reflect.DeepEqual(got, []AminoAcid{'D', 'N'}) {
+		t.Errorf("expected ambiguous table entry to be expanded, got %q", got)
+	}
+	if got := custom.TranslateCodon("AAU"); got != 'B' {
+		t.Errorf("expected B, got %c", got)
+	}
+}
+
 func TestCodonTable_ModifyCodonUsage(t *testing.T) {
 	codonTable := &CodonTable{
 		// Set up a simple codon table
@@ -163,3 +201,152 @@ func TestCodonTable_Copy(t *testing.T) {
 		})
 	}
 }
+
+const experimentalPRT = `Genetic-code-table ::= {
+ {
+  name "Experimental Recoded" ,
+  name "EXP1" ,
+  id 101 ,
+  ncbieaa  "FFLLSSSSYY*WCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+  sncbieaa "---M------*---*---------------------M---------------------------"
+  -- Base1  TTTTTTTTTTTTTTTTCCCCCCCCCCCCCCCCAAAAAAAAAAAAAAAAGGGGGGGGGGGGGGGG
+  -- Base2  TTTTCCCCAAAAGGGGTTTTCCCCAAAAGGGGTTTTCCCCAAAAGGGGTTTTCCCCAAAAGGGG
+  -- Base3  TCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAG
+ }
+}`
+
+func TestRegisterCodonTables(t *testing.T) {
+	defer ResetCodonTables()
+
+	tables, err := ParseCodonTables(strings.NewReader(experimentalPRT))
+	if err != nil {
+		t.Fatalf("ParseCodonTables() error = %v", err)
+	}
+
+	builtins := len(CodonTables())
+
+	err = RegisterCodonTables(tables...)
+	if err != nil {
+		t.Fatalf("RegisterCodonTables() error = %v", err)
+	}
+
+	if got := len(CodonTables()); got != builtins+1 {
+		t.Errorf("expected %d tables after registration, got %d", builtins+1, got)
+	}
+
+	table, err := GetCodonTable(101)
+	if err != nil {
+		t.Fatalf("GetCodonTable() error = %v", err)
+	}
+	if table.Name != "Experimental Recoded" || table.Codons["UAG"] != 'W' {
+		t.Errorf("unexpected registered table: %s, UAG -> %c", table.Name, table.Codons["UAG"])
+	}
+
+	// registered tables are copied and can't be changed through the caller's slice
+	tables[0].Codons["UAG"] = '*'
+	table, _ = GetCodonTable(101)
+	if table.Codons["UAG"] != 'W' {
+		t.Errorf("registered table was modified through the original map")
+	}
+
+	if _, err = GetCodonTable(1); err != nil {
+		t.Errorf("built-in table must stay available after registration: %v", err)
+	}
+
+	// registering a known id is left to ReplaceCodonTables
+	standard, _ := GetCodonTable(1)
+	standard.Name = "Standard (patched)"
+	if err = RegisterCodonTables(standard); err == nil {
+		t.Errorf("expected error when registering known table no. 1")
+	}
+	if table, _ = GetCodonTable(1); table.Name != "Standard" {
+		t.Errorf("expected table 1 to stay unchanged, got %q", table.Name)
+	}
+}
+
+func TestReplaceCodonTables(t *testing.T) {
+	defer ResetCodonTables()
+
+	tables, err := ParseCodonTables(strings.NewReader(experimentalPRT))
+	if err != nil {
+		t.Fatalf("ParseCodonTables() error = %v", err)
+	}
+
+	err = ReplaceCodonTables(tables...)
+	if err != nil {
+		t.Fatalf("ReplaceCodonTables() error = %v", err)
+	}
+
+	if got := len(CodonTables()); got != 1 {
+		t.Errorf("expected only the loaded table, got %d tables", got)
+	}
+	if _, err = GetCodonTable(1); err == nil {
+		t.Errorf("expected built-in table 1 to be unavailable")
+	}
+
+	// aliases of the replaced tables are dropped and free to use again
+	if _, err = LookupCodonTable("standard"); err == nil {
+		t.Errorf("expected alias of built-in table 1 to be dropped")
+	}
+	if err = AddCodonTableAlias("standard", 101); err != nil {
+		t.Errorf("AddCodonTableAlias() error = %v", err)
+	}
+
+	// a rejected batch leaves the active tables untouched
+	clash := tables[0].Copy()
+	clash.ID = 102
+	if err = ReplaceCodonTables(tables[0], clash); err == nil {
+		t.Errorf("expected error for tables sharing a name")
+	}
+	if got := len(CodonTables()); got != 1 {
+		t.Errorf("expected the loaded table to stay active, got %d tables", got)
+	}
+
+	ResetCodonTables()
+	if _, err = GetCodonTable(101); err == nil {
+		t.Errorf("expected table 101 to be removed by reset")
+	}
+	if _, err = GetCodonTable(1); err != nil {
+		t.Errorf("expected table 1 after reset: %v", err)
+	}
+}
+
+func TestRegisterCodonTables_Invalid(t *testing.T) {
+	defer ResetCodonTables()
+
+	standard, _ := GetCodonTable(1)
+	badCodon := standard.Copy()
+	badCodon.ID = 200
+	badCodon.Codons["ZZZ"] = 'A'
+	renamed := func(id int, name string) CodonTable {
+		table := standard.Copy()
+		table.ID, table.Name, table.Description = id, name, ""
+		return table
+	}
+
+	tests := []struct {
+		name   string
+		tables []CodonTable
+	}{
+		{name: "empty", tables: nil},
+		{name: "zero-id", tables: []CodonTable{{Name: "no id", Codons: standard.Codons}}},
+		{name: "no-codons", tables: []CodonTable{{ID: 200, Name: "empty"}}},
+		{name: "invalid-codon", tables: []CodonTable{badCodon}},
+		{name: "duplicate-ids", tables: []CodonTable{standard, standard}},
+		{name: "known-id", tables: []CodonTable{renamed(1, "Other Standard")}},
+		{name: "name-taken", tables: []CodonTable{renamed(200, "standard")}},
+		{name: "name-taken-in-batch", tables: []CodonTable{renamed(200, "Custom"), renamed(201, "CUSTOM")}},
+	}
+
+	builtins := len(CodonTables())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterCodonTables(tt.tables...); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if got := len(CodonTables()); got != builtins {
+				t.Errorf("rejected tables must not be registered, got %d tables", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package sequence
+
+// BaseComposition describes the letters of a DNA or RNA sequence, e.g. of a draft assembly with N runs and gaps.
+type BaseComposition struct {
+	// Counts are the numbers of every uppercase letter, U and T are counted separately.
+	Counts map[Nucleotide]int
+	Length int
+	// Ns is the number of N bases, Gaps the number of '-' and '.' letters.
+	Ns   int
+	Gaps int
+	// A, C, G and T (T or U) are the base counts with the IUPAC codes other than N credited fractionally,
+	// e.g. R as half A and half G and S as one G or C.
+	A, C, G, T float64
+}
+
+// Composition counts the letters of seq, ignoring case.
+func Composition[S DNASequence | RNASequence](seq S) BaseComposition {
+	composition := BaseComposition{
+		Counts: make(map[Nucleotide]int),
+		Length: len(seq),
+	}
+
+	var letters [256]int
+	for i := 0; i < len(seq); i++ {
+		letters[upper(seq[i])]++
+	}
+
+	for letter, count := range letters {
+		if count == 0 {
+			continue
+		}
+		composition.Counts[Nucleotide(letter)] = count
+
+		switch letter {
+		case 'N':
+			composition.Ns += count
+			continue
+		case '-', '.':
+			composition.Gaps += count
+			continue
+		case 'T':
+			letter = 'U'
+		}
+
+		bases, isAmbiguous := AmbiguousNucleotidesMap[Nucleotide(letter)]
+		if !isAmbiguous {
+			bases = []Nucleotide{Nucleotide(letter)}
+		}
+
+		credit := float64(count) / float64(len(bases))
+		for _, base := range bases {
+			switch base {
+			case 'A':
+				composition.A += credit
+			case 'C':
+				composition.C += credit
+			case 'G':
+				composition.G += credit
+			case 'U':
+				composition.T += credit
+			}
+		}
+	}
+
+	return composition
+}
+
+// ResolvableBases returns the number of bases that are neither N nor gaps nor invalid letters.
+func (c BaseComposition) ResolvableBases() float64 {
+	return c.A + c.C + c.G + c.T
+}
+
+// GCContent returns the GC fraction of the resolvable bases, unlike the GCContent function it isn't diluted
+// by N runs and gaps and credits S, R, Y and other IUPAC codes.
+func (c BaseComposition) GCContent() float64 {
+	return ratio(c.G+c.C, c.ResolvableBases())
+}
+
+func (c BaseComposition) NFraction() float64 {
+	return ratio(float64(c.Ns), float64(c.Length))
+}
+
+func (c BaseComposition) GapFraction() float64 {
+	return ratio(float64(c.Gaps), float64(c.Length))
+}
@@ -0,0 +1,73 @@
+package sequence
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestComposition(t *testing.T) {
+	tests := []struct {
+		name        string
+		composition BaseComposition
+		expected    BaseComposition
+		gc          float64
+		nFraction   float64
+		gapFraction float64
+	}{
+		{
+			name:        "draft-assembly",
+			composition: Composition(DNASequence("ACGTnnnn--SW")),
+			expected: BaseComposition{
+				Counts: map[Nucleotide]int{'A': 1, 'C': 1, 'G': 1, 'T': 1, 'N': 4, '-': 2, 'S': 1, 'W': 1},
+				Length: 12,
+				Ns:     4,
+				Gaps:   2,
+				A:      1.5, C: 1.5, G: 1.5, T: 1.5,
+			},
+			gc:          0.5,
+			nFraction:   4 / 12.,
+			gapFraction: 2 / 12.,
+		},
+		{
+			name:        "rna",
+			composition: Composition(RNASequence("GGSU.R")),
+			expected: BaseComposition{
+				Counts: map[Nucleotide]int{'G': 2, 'S': 1, 'U': 1, '.': 1, 'R': 1},
+				Length: 6,
+				Gaps:   1,
+				A:      0.5, C: 0.5, G: 3, T: 1,
+			},
+			gc:          3.5 / 5,
+			gapFraction: 1 / 6.,
+		},
+		{
+			name:        "only-n",
+			composition: Composition(DNASequence("NN")),
+			expected:    BaseComposition{Counts: map[Nucleotide]int{'N': 2}, Length: 2, Ns: 2},
+			nFraction:   1,
+		},
+		{
+			name:        "empty",
+			composition: Composition(DNASequence("")),
+			expected:    BaseComposition{Counts: map[Nucleotide]int{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.composition, tt.expected) {
+				t.Errorf("Composition() = %+v, expected %+v", tt.composition, tt.expected)
+			}
+			if gc := tt.composition.GCContent(); math.Abs(gc-tt.gc) > 1e-9 {
+				t.Errorf("GCContent() = %v, expected %v", gc, tt.gc)
+			}
+			if n := tt.composition.NFraction(); math.Abs(n-tt.nFraction) > 1e-9 {
+				t.Errorf("NFraction() = %v, expected %v", n, tt.nFraction)
+			}
+			if gaps := tt.composition.GapFraction(); math.Abs(gaps-tt.gapFraction) > 1e-9 {
+				t.Errorf("GapFraction() = %v, expected %v", gaps, tt.gapFraction)
+			}
+		})
+	}
+}
@@ -7,32 +7,46 @@ import (
 
 type DNASequence string
 
+// complementMapForDNA keeps the case of bases, so soft-masked regions can stay lowercase
 var complementMapForDNA = map[Nucleotide]Nucleotide{
-	'A': 'T', 'a': 'T',
-	'T': 'A', 't': 'A',
-	'C': 'G', 'c': 'G',
-	'G': 'C', 'g': 'C',
-	'R': 'Y', 'r': 'Y',
-	'Y': 'R', 'y': 'R',
-	'S': 'S', 's': 'S',
-	'W': 'W', 'w': 'W',
-	'K': 'M', 'k': 'M',
-	'M': 'K', 'm': 'K',
-	'B': 'V', 'b': 'V',
-	'D': 'H', 'd': 'H',
-	'H': 'D', 'h': 'D',
-	'V': 'B', 'v': 'B',
-	'N': 'N', 'n': 'N',
+	'A': 'T', 'a': 't',
+	'T': 'A', 't': 'a',
+	'C': 'G', 'c': 'g',
+	'G': 'C', 'g': 'c',
+	'R': 'Y', 'r': 'y',
+	'Y': 'R', 'y': 'r',
+	'S': 'S', 's': 's',
+	'W': 'W', 'w': 'w',
+	'K': 'M', 'k': 'm',
+	'M': 'K', 'm': 'k',
+	'B': 'V', 'b': 'v',
+	'D': 'H', 'd': 'h',
+	'H': 'D', 'h': 'd',
+	'V': 'B', 'v': 'b',
+	'N': 'N', 'n': 'n',
 	'-': '-',
 }
 
+var ErrDNAContainsU = errors.New("string contains U base and is not valid DNA sequence")
+
+// NewDNASequence accepts IUPAC nucleotides and gaps in any case and returns the uppercase sequence.
 func NewDNASequence(input string) (DNASequence, error) {
-	upper := strings.ToUpper(input)
-	if strings.Contains(upper, "U") {
-		return "", errors.New("string contains U base and is not valid DNA sequence")
+	return NewDNASequenceWithOptions(input, SequenceOptions{})
+}
+
+// NewDNASequenceWithAlphabet returns an *InvalidCharacterError for characters missing from the alphabet,
+// it wraps ErrDNAContainsU if the character is U.
+func NewDNASequenceWithAlphabet(input string, alphabet *Alphabet) (DNASequence, error) {
+	return NewDNASequenceWithOptions(input, SequenceOptions{Alphabet: alphabet})
+}
+
+func NewDNASequenceWithOptions(input string, options SequenceOptions) (DNASequence, error) {
+	err := validateSequence(input, options.alphabet(DNAGappedAlphabet), 'U', ErrDNAContainsU)
+	if err != nil {
+		return "", err
 	}
 
-	return DNASequence(upper), nil
+	return DNASequence(options.applyCase(input)), nil
 }
 
 func (d DNASequence) String() string {
@@ -55,6 +69,11 @@ func (d DNASequence) Reverse() DNASequence {
 }
 
 func (d DNASequence) Complement() DNASequence {
+	return d.ComplementWithOptions(SequenceOptions{})
+}
+
+// ComplementWithOptions keeps soft-masked (lowercase) bases with PreserveCase, the alphabet is ignored.
+func (d DNASequence) ComplementWithOptions(options SequenceOptions) DNASequence {
 	complement := make([]Nucleotide, len(d))
 
 	for i, base := range []Nucleotide(d) {
@@ -66,22 +85,41 @@ func (d DNASequence) Complement() DNASequence {
 		}
 	}
 
-	return DNASequence(complement)
+	return DNASequence(options.applyCase(string(complement)))
 }
 
 func (d DNASequence) ReverseComplement() DNASequence {
-	return d.Reverse().Complement()
+	return d.ReverseComplementWithOptions(SequenceOptions{})
 }
 
-func (d DNASequence) Transcribe() RNASequence {
-	transcriptionMap := complementMapForDNA
+func (d DNASequence) ReverseComplementWithOptions(options SequenceOptions) DNASequence {
+	return d.Reverse().ComplementWithOptions(options)
+}
+
+// transcriptionMapForDNA pairs template strand DNA bases with RNA bases of the same case
+var transcriptionMapForDNA = func() map[Nucleotide]Nucleotide {
+	transcriptionMap := make(map[Nucleotide]Nucleotide, len(complementMapForDNA))
+	for base, complement := range complementMapForDNA {
+		transcriptionMap[base] = complement
+	}
 	transcriptionMap['A'] = 'U'
-	transcriptionMap['a'] = 'U'
+	transcriptionMap['a'] = 'u'
+
+	return transcriptionMap
+}()
 
+// Transcribe pairs every base of d, read as the template strand, with its RNA base.
+// The result is not reversed, so it runs 3'->5'. Use TranscribeTemplate for the 5'->3' transcript.
+func (d DNASequence) Transcribe() RNASequence {
+	return d.TranscribeWithOptions(SequenceOptions{})
+}
+
+// TranscribeWithOptions keeps soft-masked (lowercase) bases with PreserveCase, the alphabet is ignored.
+func (d DNASequence) TranscribeWithOptions(options SequenceOptions) RNASequence {
 	rna := make([]Nucleotide, len(d))
 
 	for i, base := range []Nucleotide(d) {
-		rnaBase, ok := transcriptionMap[base]
+		rnaBase, ok := transcriptionMapForDNA[base]
 		if !ok {
 			rna[i] = '-'
 		} else {
@@ -89,5 +127,125 @@ func (d DNASequence) Transcribe() RNASequence {
 		}
 	}
 
-	return RNASequence(rna)
+	return RNASequence(options.applyCase(string(rna)))
+}
+
+// TranscribeCoding returns the mRNA of d read as the coding strand, i.e. d with T replaced by U.
+func (d DNASequence) TranscribeCoding() RNASequence {
+	return d.TranscribeCodingWithOptions(SequenceOptions{})
+}
+
+func (d DNASequence) TranscribeCodingWithOptions(options SequenceOptions) RNASequence {
+	return RNASequence(options.applyCase(strings.Map(func(r rune) rune {
+		switch r {
+		case 'T':
+			return 'U'
+		case 't':
+			return 'u'
+		}
+		return r
+	}, string(d))))
+}
+
+// TranscribeTemplate returns the 5'->3' mRNA of d read as the template strand.
+func (d DNASequence) TranscribeTemplate() RNASequence {
+	return d.TranscribeTemplateWithOptions(SequenceOptions{})
+}
+
+func (d DNASequence) TranscribeTemplateWithOptions(options SequenceOptions) RNASequence {
+	return d.ReverseComplementWithOptions(options).TranscribeCodingWithOptions(options)
+}
+
+type FrameTranslation struct {
+	Strand  Strand
+	Frame   int
+	Protein ProteinSequence
+}
+
+// TranslateSixFrames translates the three forward frames and the three frames of the reverse complement.
+// Trailing partial codons are ignored.
+func (d DNASequence) TranslateSixFrames(codonTable *CodonTable) ([]FrameTranslation, error) {
+	if len(d) < 3 {
+		return nil, ErrTooShortSequence
+	}
+
+	translations := make([]FrameTranslation, 0, 6)
+	for _, strand := range []Strand{ForwardStrand, ReverseStrand} {
+		rna := d.strandRNA(strand)
+
+		for frame := 0; frame < 3; frame++ {
+			var protein ProteinSequence
+			if len(rna)-frame >= 3 {
+				var err error
+				protein, err = rna[frame:].Translate(codonTable)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			translations = append(translations, FrameTranslation{
+				Strand:  strand,
+				Frame:   frame + 1,
+				Protein: protein,
+			})
+		}
+	}
+
+	return translations, nil
+}
+
+// FindORFs searches all six reading frames. ORFs on the reverse strand are reported in forward-strand coordinates.
+func (d DNASequence) FindORFs(options ORFOptions) ([]ORF, error) {
+	var orfs []ORF
+	seqLength := len(d)
+
+	for _, strand := range []Strand{ForwardStrand, ReverseStrand} {
+		strandORFs, err := d.strandRNA(strand).FindORFs(options)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, orf := range strandORFs {
+			if strand == ReverseStrand {
+				// map the reverse complement span [Start, End) back to the forward strand
+				span := orf.End - orf.Start
+				if span <= 0 {
+					span += seqLength
+				}
+				orf.Start = ((seqLength-orf.Start-span)%seqLength + seqLength) % seqLength
+				orf.End = orf.Start + span
+				if orf.End > seqLength {
+					orf.End -= seqLength
+				}
+				orf.Strand = ReverseStrand
+			}
+			orfs = append(orfs, orf)
+		}
+	}
+
+	return orfs, nil
+}
+
+// ExtractORF returns the coding strand DNA of an ORF found in d, ORFs across the origin of a circular sequence included.
+func (d DNASequence) ExtractORF(orf ORF) DNASequence {
+	var fragment DNASequence
+	if orf.End > orf.Start {
+		fragment = d[orf.Start:orf.End]
+	} else {
+		fragment = d[orf.Start:] + d[:orf.End]
+	}
+
+	if orf.Strand == ReverseStrand {
+		return fragment.ReverseComplement()
+	}
+	return fragment
+}
+
+// strandRNA returns the uppercase mRNA of the strand
+func (d DNASequence) strandRNA(strand Strand) RNASequence {
+	if strand == ReverseStrand {
+		return d.TranscribeTemplate()
+	}
+
+	return d.TranscribeCoding()
 }
@@ -1,17 +1,19 @@
-package parser
+package sequence
 
 import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
-
-	"github.com/dissipative/ribosome/pkg/sequence"
 )
 
-func ParsePRTCodonTables(scanner *bufio.Scanner) ([]sequence.CodonTable, error) {
-	var tables []sequence.CodonTable
+// ParseCodonTables reads genetic codes in the NCBI gc.prt format.
+func ParseCodonTables(reader io.Reader) ([]CodonTable, error) {
+	var tables []CodonTable
+	scanner := bufio.NewScanner(reader)
 	parser := newGeneticCodesParser()
 
 	for scanner.Scan() {
@@ -34,7 +36,10 @@ func ParsePRTCodonTables(scanner *bufio.Scanner) ([]sequence.CodonTable, error)
 		}
 
 		parser.processNameAndDescription(line)
-		parser.processCodons(line)
+		err = parser.processCodons(line)
+		if err != nil {
+			return nil, err
+		}
 
 		if parser.table.ID != 0 && parser.table.Name != "" && len(parser.table.Codons) > 0 {
 			tables = append(tables, *parser.table)
@@ -52,6 +57,16 @@ func ParsePRTCodonTables(scanner *bufio.Scanner) ([]sequence.CodonTable, error)
 	return tables, nil
 }
 
+func ParseCodonTablesFile(filename string) ([]CodonTable, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseCodonTables(file)
+}
+
 func removeTagAndTrim(line string, tag string) string {
 	line = strings.Replace(line, tag, "", 1)
 	line = strings.ReplaceAll(line, "\"", "")
@@ -62,7 +77,7 @@ func removeTagAndTrim(line string, tag string) string {
 type geneticCodesParser struct {
 	nameIsUsed   bool
 	multiline    bool
-	table        *sequence.CodonTable
+	table        *CodonTable
 	ncbiCodeData *ncbiCodeData
 }
 
@@ -76,10 +91,10 @@ type ncbiCodeData struct {
 
 func newGeneticCodesParser() *geneticCodesParser {
 	return &geneticCodesParser{
-		table: &sequence.CodonTable{
-			Codons:      make(map[string]sequence.AminoAcid),
-			StartCodons: make(map[string]sequence.AminoAcid),
-			StopCodons:  make(map[string]sequence.AminoAcid),
+		table: &CodonTable{
+			Codons:      make(map[string]AminoAcid),
+			StartCodons: make(map[string]AminoAcid),
+			StopCodons:  make(map[string]AminoAcid),
 		},
 		ncbiCodeData: &ncbiCodeData{},
 	}
@@ -133,7 +148,7 @@ func (gcp *geneticCodesParser) processID(line string) error {
 	return nil
 }
 
-func (gcp *geneticCodesParser) processCodons(line string) {
+func (gcp *geneticCodesParser) processCodons(line string) error {
 	if strings.Contains(line, "sncbieaa") {
 		gcp.ncbiCodeData.sncbieaa = removeTagAndTrim(line, "sncbieaa")
 	}
@@ -152,25 +167,37 @@ func (gcp *geneticCodesParser) processCodons(line string) {
 	if gcp.ncbiCodeData != nil && gcp.ncbiCodeData.sncbieaa != "" &&
 		gcp.ncbiCodeData.ncbieaa != "" && gcp.ncbiCodeData.base1 != "" &&
 		gcp.ncbiCodeData.base2 != "" && gcp.ncbiCodeData.base3 != "" {
-		gcp.parseNCBICodeData()
+		return gcp.parseNCBICodeData()
 	}
+
+	return nil
 }
 
-func (gcp *geneticCodesParser) parseNCBICodeData() {
+func (gcp *geneticCodesParser) parseNCBICodeData() error {
+	data := gcp.ncbiCodeData
+	for _, field := range []string{data.ncbieaa, data.sncbieaa, data.base1, data.base2, data.base3} {
+		if len(field) != 64 {
+			return fmt.Errorf("codon table no. %d: expected 64 codons in ncbieaa, sncbieaa and base lines, got %d",
+				gcp.table.ID, len(field))
+		}
+	}
+
 	for i := 0; i < len(gcp.ncbiCodeData.ncbieaa); i++ {
 		codon := string([]byte{gcp.ncbiCodeData.base1[i], gcp.ncbiCodeData.base2[i], gcp.ncbiCodeData.base3[i]})
 		// DNA -> RNA
 		codon = strings.ReplaceAll(codon, "T", "U")
-		gcp.table.Codons[codon] = sequence.AminoAcid(gcp.ncbiCodeData.ncbieaa[i])
+		gcp.table.Codons[codon] = AminoAcid(gcp.ncbiCodeData.ncbieaa[i])
 
 		if gcp.ncbiCodeData.sncbieaa[i] == '-' {
 			continue
 		}
 		if gcp.ncbiCodeData.sncbieaa[i] == '*' {
-			gcp.table.StopCodons[codon] = sequence.AminoAcid(gcp.ncbiCodeData.ncbieaa[i])
+			gcp.table.StopCodons[codon] = AminoAcid(gcp.ncbiCodeData.ncbieaa[i])
 		}
 		if gcp.ncbiCodeData.sncbieaa[i] == 'M' {
-			gcp.table.StartCodons[codon] = sequence.AminoAcid(gcp.ncbiCodeData.ncbieaa[i])
+			gcp.table.StartCodons[codon] = AminoAcid(gcp.ncbiCodeData.ncbieaa[i])
 		}
 	}
+
+	return nil
 }
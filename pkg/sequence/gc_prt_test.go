@@ -1,14 +1,11 @@
-package parser
+package sequence
 
 import (
-	"bufio"
 	"strings"
 	"testing"
-
-	"github.com/dissipative/ribosome/pkg/sequence"
 )
 
-func TestParsePRTCodonTables(t *testing.T) {
+func TestParseCodonTables(t *testing.T) {
 	testInput := `--*************************************************************************
 
 Genetic-code-table ::= {
@@ -64,28 +61,28 @@ Genetic-code-table ::= {
  }
 }`
 
-	standardTable, err := sequence.GetCodonTable(1)
+	standardTable, err := GetCodonTable(1)
 	if err != nil {
 		t.Error(err)
 	}
-	vmTable, err := sequence.GetCodonTable(2)
+	vmTable, err := GetCodonTable(2)
 	if err != nil {
 		t.Error(err)
 	}
-	moldMtTable, err := sequence.GetCodonTable(4)
+	moldMtTable, err := GetCodonTable(4)
 	if err != nil {
 		t.Error(err)
 	}
-	euplotidTable, err := sequence.GetCodonTable(10)
+	euplotidTable, err := GetCodonTable(10)
 	if err != nil {
 		t.Error(err)
 	}
-	bacterialTable, err := sequence.GetCodonTable(11)
+	bacterialTable, err := GetCodonTable(11)
 	if err != nil {
 		t.Error(err)
 	}
 
-	expectedResult := []sequence.CodonTable{
+	expectedResult := []CodonTable{
 		{
 			ID:          1,
 			Name:        "Standard",
@@ -118,8 +115,7 @@ Genetic-code-table ::= {
 		},
 	}
 
-	scanner := bufio.NewScanner(strings.NewReader(testInput))
-	result, err := ParsePRTCodonTables(scanner)
+	result, err := ParseCodonTables(strings.NewReader(testInput))
 	if err != nil {
 		t.Error(err)
 	}
@@ -144,3 +140,39 @@ Genetic-code-table ::= {
 		}
 	}
 }
+
+func TestParseCodonTables_Malformed(t *testing.T) {
+	const (
+		ncbieaa  = "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG"
+		sncbieaa = "---M------**--*----M---------------M----------------------------"
+		base1    = "TTTTTTTTTTTTTTTTCCCCCCCCCCCCCCCCAAAAAAAAAAAAAAAAGGGGGGGGGGGGGGGG"
+		base2    = "TTTTCCCCAAAAGGGGTTTTCCCCAAAAGGGGTTTTCCCCAAAAGGGGTTTTCCCCAAAAGGGG"
+		base3    = "TCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAGTCAG"
+	)
+	table := func(ncbieaa, sncbieaa, base1 string) string {
+		return "Genetic-code-table ::= {\n{\n  name \"Broken\" ,\n  id 7 ,\n" +
+			"  ncbieaa  \"" + ncbieaa + "\",\n  sncbieaa \"" + sncbieaa + "\"\n" +
+			"  -- Base1  " + base1 + "\n  -- Base2  " + base2 + "\n  -- Base3  " + base3 + "\n}\n}"
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "long-ncbieaa", input: table(ncbieaa+"G", sncbieaa, base1)},
+		{name: "short-sncbieaa", input: table(ncbieaa, sncbieaa[:60], base1)},
+		{name: "short-base", input: table(ncbieaa, sncbieaa, base1[:63])},
+	}
+
+	if _, err := ParseCodonTables(strings.NewReader(table(ncbieaa, sncbieaa, base1))); err != nil {
+		t.Fatalf("ParseCodonTables() of the well-formed table error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCodonTables(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), "no. 7") {
+				t.Errorf("expected an error naming table no. 7, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package sequence
+
+// Interval is a 0-based, end-exclusive range of positions.
+type Interval struct {
+	Start int
+	End   int
+}
+
+func (i Interval) Length() int {
+	return i.End - i.Start
+}
+
+// MaskedIntervals returns the runs of lowercase (soft-masked) bases, e.g. repeats marked by RepeatMasker.
+func MaskedIntervals[S DNASequence | RNASequence](seq S) []Interval {
+	var intervals []Interval
+
+	start := -1
+	for i := 0; i < len(seq); i++ {
+		masked := isSoftMasked(seq[i])
+		if masked && start < 0 {
+			start = i
+		}
+		if !masked && start >= 0 {
+			intervals = append(intervals, Interval{Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		intervals = append(intervals, Interval{Start: start, End: len(seq)})
+	}
+
+	return intervals
+}
+
+// HardMask replaces soft-masked (lowercase) bases with N.
+func HardMask[S DNASequence | RNASequence](seq S) S {
+	masked := []byte(seq)
+	for i, base := range masked {
+		if isSoftMasked(base) {
+			masked[i] = 'N'
+		}
+	}
+
+	return S(masked)
+}
+
+func isSoftMasked(base byte) bool {
+	return base >= 'a' && base <= 'z'
+}
@@ -0,0 +1,71 @@
+package sequence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSoftMasking(t *testing.T) {
+	dna, err := NewDNASequenceWithOptions("ACGtacGTnnA", SequenceOptions{PreserveCase: true})
+	if err != nil {
+		t.Fatalf("NewDNASequenceWithOptions() error = %v", err)
+	}
+	if dna != "ACGtacGTnnA" {
+		t.Errorf("expected case to be preserved, got %s", dna)
+	}
+
+	expectedIntervals := []Interval{{Start: 3, End: 6}, {Start: 8, End: 10}}
+	if got := MaskedIntervals(dna); !reflect.DeepEqual(got, expectedIntervals) {
+		t.Errorf("MaskedIntervals() = %v, expected %v", got, expectedIntervals)
+	}
+	if got := HardMask(dna); got != "ACGNNNGTNNA" {
+		t.Errorf("HardMask() = %s, expected ACGNNNGTNNA", got)
+	}
+
+	preserveCase := SequenceOptions{PreserveCase: true}
+	if got := dna.ComplementWithOptions(preserveCase); got != "TGCatgCAnnT" {
+		t.Errorf("ComplementWithOptions() = %s", got)
+	}
+	if got := dna.ReverseComplementWithOptions(preserveCase); got != "TnnACgtaCGT" {
+		t.Errorf("ReverseComplementWithOptions() = %s", got)
+	}
+	if got := dna.TranscribeWithOptions(preserveCase); got != "UGCaugCAnnU" {
+		t.Errorf("TranscribeWithOptions() = %s", got)
+	}
+	if got := dna.TranscribeCodingWithOptions(preserveCase); got != "ACGuacGUnnA" {
+		t.Errorf("TranscribeCodingWithOptions() = %s", got)
+	}
+	if got := dna.TranscribeTemplateWithOptions(preserveCase); got != "UnnACguaCGU" {
+		t.Errorf("TranscribeTemplateWithOptions() = %s", got)
+	}
+	if got := MaskedIntervals(dna.TranscribeCodingWithOptions(preserveCase)); !reflect.DeepEqual(got, expectedIntervals) {
+		t.Errorf("MaskedIntervals() of transcript = %v, expected %v", got, expectedIntervals)
+	}
+
+	// without PreserveCase the operations return uppercase sequences
+	if got := dna.Complement(); got != "TGCATGCANNT" {
+		t.Errorf("Complement() = %s", got)
+	}
+	if got := dna.ReverseComplement(); got != "TNNACGTACGT" {
+		t.Errorf("ReverseComplement() = %s", got)
+	}
+	if got := dna.Transcribe(); got != "UGCAUGCANNU" {
+		t.Errorf("Transcribe() = %s", got)
+	}
+
+	if upper, _ := NewDNASequence("ACGtac"); upper != "ACGTAC" || MaskedIntervals(upper) != nil {
+		t.Errorf("expected NewDNASequence to upper-case its input, got %s", upper)
+	}
+}
+
+func TestSoftMasking_FindORFs(t *testing.T) {
+	dna, _ := NewDNASequenceWithOptions("atgCCCtaa", SequenceOptions{PreserveCase: true})
+
+	orfs, err := dna.FindORFs(ORFOptions{})
+	if err != nil {
+		t.Fatalf("FindORFs() error = %v", err)
+	}
+	if len(orfs) != 1 || orfs[0].ProteinSeq != "MP*" {
+		t.Errorf("expected ORF in soft-masked sequence, got %+v", orfs)
+	}
+}
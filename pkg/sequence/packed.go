@@ -0,0 +1,385 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxPackedKMer is the longest k-mer that fits into uint64 with 2 bits per base.
+const MaxPackedKMer = 32
+
+var ErrInvalidKMerSize = errors.New("k-mer size must be between 1 and 32")
+
+// PackedSequence stores a nucleotide sequence compactly with 2 bits per base (A=0, C=1, G=2, T/U=3). IUPAC ambiguity
+// codes and gaps are kept aside as runs of positions sharing a 4-bit mask of the possible bases (A=1, C=2, G=4,
+// T/U=8, gap=0), so a genome with a few stretches of N still takes about a quarter of a byte per base.
+// Case is not preserved.
+//
+// Slices share the packed data with the sequence they were taken from, a PackedSequence is never modified in place.
+type PackedSequence struct {
+	data []byte
+	// ambiguous runs sorted by position, positions are counted in data like offset
+	ambiguous []ambiguousRun
+	offset    int
+	length    int
+	rna       bool
+}
+
+// ambiguousRun is a stretch [start, end) of the same ambiguity code or gap.
+type ambiguousRun struct {
+	start, end int
+	mask       byte
+}
+
+var (
+	// packedCodes map a letter to its 2-bit code, -1 for ambiguous or invalid letters
+	packedCodes [256]int8
+	// packedMasks map a letter to its 4-bit IUPAC mask, -1 for invalid letters
+	packedMasks [256]int8
+	// complementMasks swap A<->T and C<->G in a 4-bit mask
+	complementMasks [16]byte
+	// reverseComplementCodes reverse the order of bases in a byte and complement them
+	reverseComplementCodes [256]byte
+)
+
+const (
+	dnaMaskLetters = "-ACMGRSVTWYHKDBN"
+	rnaMaskLetters = "-ACMGRSVUWYHKDBN"
+	dnaCodeLetters = "ACGT"
+	rnaCodeLetters = "ACGU"
+)
+
+func init() {
+	for i := range packedCodes {
+		packedCodes[i] = -1
+		packedMasks[i] = -1
+	}
+
+	for mask := 0; mask < 16; mask++ {
+		for _, letters := range []string{dnaMaskLetters, rnaMaskLetters} {
+			upper, lower := letters[mask], strings.ToLower(letters)[mask]
+			packedMasks[upper] = int8(mask)
+			packedMasks[lower] = int8(mask)
+		}
+		// reverse the bit order: A(1)<->T(8), C(2)<->G(4)
+		complementMasks[mask] = byte((mask&1)<<3 | (mask&2)<<1 | (mask&4)>>1 | (mask&8)>>3)
+	}
+	for code := 0; code < 4; code++ {
+		for _, letters := range []string{dnaCodeLetters, rnaCodeLetters} {
+			upper, lower := letters[code], strings.ToLower(letters)[code]
+			packedCodes[upper] = int8(code)
+			packedCodes[lower] = int8(code)
+		}
+	}
+
+	for b := range reverseComplementCodes {
+		complemented := ^byte(b)
+		for i := 0; i < 4; i++ {
+			reverseComplementCodes[b] |= (complemented >> (2 * i) & 0x3) << (6 - 2*i)
+		}
+	}
+}
+
+// PackDNA returns an *InvalidCharacterError for letters that are not gapped IUPAC DNA, as NewDNASequence does.
+func PackDNA(seq DNASequence) (PackedSequence, error) {
+	err := validateSequence(string(seq), DNAGappedAlphabet, 'U', ErrDNAContainsU)
+	if err != nil {
+		return PackedSequence{}, err
+	}
+
+	return pack(string(seq), false), nil
+}
+
+// PackRNA returns an *InvalidCharacterError for letters that are not gapped IUPAC RNA, as NewRNASequence does.
+func PackRNA(seq RNASequence) (PackedSequence, error) {
+	err := validateSequence(string(seq), RNAGappedAlphabet, 'T', ErrRNAContainsT)
+	if err != nil {
+		return PackedSequence{}, err
+	}
+
+	return pack(string(seq), true), nil
+}
+
+func pack(seq string, rna bool) PackedSequence {
+	packed := PackedSequence{length: len(seq), rna: rna}
+	packed.data = make([]byte, (len(seq)+3)/4)
+
+	for i := 0; i < len(seq); i++ {
+		code := packedCodes[seq[i]]
+		if code >= 0 {
+			packed.set(i, byte(code))
+			continue
+		}
+
+		// ambiguous positions keep code 0 in data
+		mask := byte(packedMasks[seq[i]])
+		last := len(packed.ambiguous) - 1
+		if last >= 0 && packed.ambiguous[last].end == i && packed.ambiguous[last].mask == mask {
+			packed.ambiguous[last].end++
+		} else {
+			packed.ambiguous = append(packed.ambiguous, ambiguousRun{start: i, end: i + 1, mask: mask})
+		}
+	}
+
+	return packed
+}
+
+// get returns the 2-bit code at position i, bases are stored from the most significant bits.
+func (p PackedSequence) get(i int) byte {
+	i += p.offset
+	return p.data[i/4] >> (6 - 2*(i%4)) & 0x3
+}
+
+func (p PackedSequence) set(i int, value byte) {
+	i += p.offset
+	shift := 6 - 2*(i%4)
+	p.data[i/4] = p.data[i/4]&^(0x3<<shift) | value<<shift
+}
+
+// runs returns the ambiguous runs overlapping the sequence.
+func (p PackedSequence) runs() []ambiguousRun {
+	first := sort.Search(len(p.ambiguous), func(i int) bool { return p.ambiguous[i].end > p.offset })
+	last := sort.Search(len(p.ambiguous), func(i int) bool { return p.ambiguous[i].start >= p.offset+p.length })
+	if first >= last {
+		return nil
+	}
+
+	return p.ambiguous[first:last]
+}
+
+// clip returns the positions of an overlapping run within the sequence.
+func (p PackedSequence) clip(run ambiguousRun) (start, end int) {
+	start, end = run.start-p.offset, run.end-p.offset
+	if start < 0 {
+		start = 0
+	}
+	if end > p.length {
+		end = p.length
+	}
+
+	return start, end
+}
+
+// mask returns the ambiguity mask at position i, false if the base is unambiguous.
+func (p PackedSequence) mask(i int) (byte, bool) {
+	i += p.offset
+	run := sort.Search(len(p.ambiguous), func(j int) bool { return p.ambiguous[j].end > i })
+	if run < len(p.ambiguous) && p.ambiguous[run].start <= i {
+		return p.ambiguous[run].mask, true
+	}
+
+	return 0, false
+}
+
+func (p PackedSequence) Length() int {
+	return p.length
+}
+
+// Ambiguous reports whether the sequence contains IUPAC ambiguity codes or gaps.
+func (p PackedSequence) Ambiguous() bool {
+	return len(p.runs()) > 0
+}
+
+// Base returns the uppercase base at position i.
+func (p PackedSequence) Base(i int) Nucleotide {
+	if i < 0 || i >= p.length {
+		panic(fmt.Sprintf("packed sequence index %d out of range [0:%d]", i, p.length))
+	}
+
+	if mask, ok := p.mask(i); ok {
+		letters := dnaMaskLetters
+		if p.rna {
+			letters = rnaMaskLetters
+		}
+		return Nucleotide(letters[mask])
+	}
+
+	letters := dnaCodeLetters
+	if p.rna {
+		letters = rnaCodeLetters
+	}
+	return Nucleotide(letters[p.get(i)])
+}
+
+func (p PackedSequence) String() string {
+	letters, maskLetters := dnaCodeLetters, dnaMaskLetters
+	if p.rna {
+		letters, maskLetters = rnaCodeLetters, rnaMaskLetters
+	}
+
+	unpacked := make([]byte, p.length)
+	for i := range unpacked {
+		unpacked[i] = letters[p.get(i)]
+	}
+	for _, run := range p.runs() {
+		start, end := p.clip(run)
+		for i := start; i < end; i++ {
+			unpacked[i] = maskLetters[run.mask]
+		}
+	}
+
+	return string(unpacked)
+}
+
+// DNA unpacks the sequence, U is written as T.
+func (p PackedSequence) DNA() DNASequence {
+	p.rna = false
+	return DNASequence(p.String())
+}
+
+// RNA unpacks the sequence, T is written as U.
+func (p PackedSequence) RNA() RNASequence {
+	p.rna = true
+	return RNASequence(p.String())
+}
+
+// Slice returns the bases in [start, end) without copying the packed data.
+func (p PackedSequence) Slice(start, end int) PackedSequence {
+	if start < 0 || end > p.length || start > end {
+		panic(fmt.Sprintf("packed sequence slice bounds [%d:%d] out of range [0:%d]", start, end, p.length))
+	}
+
+	p.offset += start
+	p.length = end - start
+	return p
+}
+
+// Complement complements whole bytes at once.
+func (p PackedSequence) Complement() PackedSequence {
+	first := p.offset / 4
+	last := (p.offset + p.length + 3) / 4
+
+	complement := p
+	complement.data = make([]byte, last-first)
+	complement.offset = p.offset % 4
+	for i, b := range p.data[first:last] {
+		// 3 - code swaps A<->T and C<->G
+		complement.data[i] = ^b
+	}
+
+	complement.ambiguous = nil
+	shift := first * 4
+	for _, run := range p.runs() {
+		complement.ambiguous = append(complement.ambiguous, ambiguousRun{
+			start: run.start - shift,
+			end:   run.end - shift,
+			mask:  complementMasks[run.mask],
+		})
+	}
+
+	return complement
+}
+
+// ReverseComplement reverses and complements whole bytes through a lookup table and shifts the result
+// to drop the unused bases of the last byte.
+func (p PackedSequence) ReverseComplement() PackedSequence {
+	reversed := PackedSequence{length: p.length, rna: p.rna}
+	reversed.data = make([]byte, (p.length+3)/4)
+	if p.length == 0 {
+		return reversed
+	}
+
+	first := p.offset / 4
+	last := (p.offset + p.length - 1) / 4
+	// bases of the last byte past the end of the sequence become the leading bases once reversed
+	lead := uint((last+1)*4 - p.offset - p.length)
+
+	for k := range reversed.data {
+		b := reverseComplementCodes[p.data[last-k]] << (lead * 2)
+		if lead > 0 && last-k-1 >= first {
+			b |= reverseComplementCodes[p.data[last-k-1]] >> (8 - lead*2)
+		}
+		reversed.data[k] = b
+	}
+
+	runs := p.runs()
+	for i := len(runs) - 1; i >= 0; i-- {
+		start, end := p.clip(runs[i])
+		reversed.ambiguous = append(reversed.ambiguous, ambiguousRun{
+			start: p.length - end,
+			end:   p.length - start,
+			mask:  complementMasks[runs[i].mask],
+		})
+	}
+
+	return reversed
+}
+
+// code returns the 2-bit code of the base at position i, false if the base is ambiguous.
+func (p PackedSequence) code(i int) (uint64, bool) {
+	if _, ok := p.mask(i); ok {
+		return 0, false
+	}
+
+	return uint64(p.get(i)), true
+}
+
+// KMer returns the k-mer starting at position i encoded with 2 bits per base, the first base in the most
+// significant bits. The result is false if the k-mer contains an ambiguous base or exceeds the sequence.
+func (p PackedSequence) KMer(i, k int) (uint64, bool) {
+	if k < 1 || k > MaxPackedKMer || i < 0 || i+k > p.length {
+		return 0, false
+	}
+
+	var kmer uint64
+	for j := i; j < i+k; j++ {
+		code, ok := p.code(j)
+		if !ok {
+			return 0, false
+		}
+		kmer = kmer<<2 | code
+	}
+
+	return kmer, true
+}
+
+// ForEachKMer calls fn with every k-mer of the sequence and its position, encoded as in KMer.
+// K-mers containing ambiguous bases are skipped.
+func (p PackedSequence) ForEachKMer(k int, fn func(pos int, kmer uint64)) error {
+	if k < 1 || k > MaxPackedKMer {
+		return ErrInvalidKMerSize
+	}
+
+	mask := uint64(1)<<(2*k) - 1
+	if k == MaxPackedKMer {
+		mask = ^uint64(0)
+	}
+
+	runs := p.runs()
+	var kmer uint64
+	valid := 0 // number of unambiguous bases ending at i
+	for i := 0; i < p.length; i++ {
+		if len(runs) > 0 && runs[0].start <= p.offset+i {
+			// skip the whole ambiguous run
+			i = runs[0].end - p.offset - 1
+			runs = runs[1:]
+			valid = 0
+			continue
+		}
+
+		kmer = (kmer<<2 | uint64(p.get(i))) & mask
+		valid++
+		if valid >= k {
+			fn(i-k+1, kmer)
+		}
+	}
+
+	return nil
+}
+
+// DecodeKMer converts a k-mer encoded with 2 bits per base back to DNA.
+func DecodeKMer(kmer uint64, k int) (DNASequence, error) {
+	if k < 1 || k > MaxPackedKMer {
+		return "", ErrInvalidKMerSize
+	}
+
+	decoded := make([]byte, k)
+	for i := k - 1; i >= 0; i-- {
+		decoded[i] = dnaCodeLetters[kmer&0x3]
+		kmer >>= 2
+	}
+
+	return DNASequence(decoded), nil
+}
@@ -0,0 +1,257 @@
+package sequence
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestPackDNA(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         DNASequence
+		expected      DNASequence
+		wantAmbiguous bool
+		wantErr       bool
+	}{
+		{name: "unambiguous", input: "ACGTTGCAA", expected: "ACGTTGCAA"},
+		{name: "lowercase", input: "acgtn", expected: "ACGTN", wantAmbiguous: true},
+		{name: "iupac-and-gaps", input: "ACGTRYSWKMBDHVN-", expected: "ACGTRYSWKMBDHVN-", wantAmbiguous: true},
+		{name: "empty", input: "", expected: ""},
+		{name: "invalid", input: "ACGX", wantErr: true},
+		{name: "uracil", input: "ACGU", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed, err := PackDNA(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PackDNA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := packed.DNA(); got != tt.expected {
+				t.Errorf("DNA() got = %s, expected %s", got, tt.expected)
+			}
+			if packed.Length() != len(tt.input) {
+				t.Errorf("Length() got = %d, expected %d", packed.Length(), len(tt.input))
+			}
+			if packed.Ambiguous() != tt.wantAmbiguous {
+				t.Errorf("Ambiguous() got = %v, expected %v", packed.Ambiguous(), tt.wantAmbiguous)
+			}
+		})
+	}
+}
+
+func TestPackRNA(t *testing.T) {
+	if _, err := PackDNA("AUG"); !errors.Is(err, ErrDNAContainsU) {
+		t.Errorf("PackDNA() expected ErrDNAContainsU, got %v", err)
+	}
+	if _, err := PackRNA("ATG"); !errors.Is(err, ErrRNAContainsT) {
+		t.Errorf("PackRNA() expected ErrRNAContainsT, got %v", err)
+	}
+
+	packed, err := PackRNA("AUGCCCUAA")
+	if err != nil {
+		t.Fatalf("PackRNA() error = %v", err)
+	}
+
+	if packed.String() != "AUGCCCUAA" {
+		t.Errorf("String() got = %s", packed.String())
+	}
+	if packed.DNA() != "ATGCCCTAA" {
+		t.Errorf("DNA() got = %s", packed.DNA())
+	}
+	if len(packed.data) != 3 {
+		t.Errorf("expected 9 bases to take 3 bytes, got %d", len(packed.data))
+	}
+	if GCContent(packed) != GCContent(RNASequence("AUGCCCUAA")) {
+		t.Errorf("GCContent() differs for packed sequence")
+	}
+}
+
+func TestPackedSequence_Complement(t *testing.T) {
+	tests := []struct {
+		name              string
+		input             DNASequence
+		start, end        int
+		complement        DNASequence
+		reverseComplement DNASequence
+	}{
+		{
+			name:              "unambiguous",
+			input:             "ATGCCCTAAG",
+			start:             0,
+			end:               10,
+			complement:        "TACGGGATTC",
+			reverseComplement: "CTTAGGGCAT",
+		},
+		{
+			name:              "unaligned-slice",
+			input:             "ATGCCCTAAG",
+			start:             3,
+			end:               8,
+			complement:        "GGGAT",
+			reverseComplement: "TAGGG",
+		},
+		{
+			name:              "ambiguous",
+			input:             "ACGTRYSWKMBDHVN-",
+			start:             0,
+			end:               16,
+			complement:        "TGCAYRSWMKVHDBN-",
+			reverseComplement: "-NBDHVKMWSRYACGT",
+		},
+		{
+			name:              "ambiguous-unaligned-slice",
+			input:             "ANGTRY",
+			start:             1,
+			end:               4,
+			complement:        "NCA",
+			reverseComplement: "ACN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed, err := PackDNA(tt.input)
+			if err != nil {
+				t.Fatalf("PackDNA() error = %v", err)
+			}
+			packed = packed.Slice(tt.start, tt.end)
+
+			if got := packed.DNA(); got != tt.input[tt.start:tt.end] {
+				t.Errorf("Slice() got = %s, expected %s", got, tt.input[tt.start:tt.end])
+			}
+			if got := packed.Complement().DNA(); got != tt.complement {
+				t.Errorf("Complement() got = %s, expected %s", got, tt.complement)
+			}
+			if got := packed.ReverseComplement().DNA(); got != tt.reverseComplement {
+				t.Errorf("ReverseComplement() got = %s, expected %s", got, tt.reverseComplement)
+			}
+			if got := packed.DNA(); got != tt.input[tt.start:tt.end] {
+				t.Errorf("complement modified the original sequence: %s", got)
+			}
+		})
+	}
+}
+
+func TestPackedSequence_KMers(t *testing.T) {
+	packed, err := PackDNA("ACGTNACGA")
+	if err != nil {
+		t.Fatalf("PackDNA() error = %v", err)
+	}
+
+	var positions []int
+	var kmers []DNASequence
+	err = packed.ForEachKMer(3, func(pos int, kmer uint64) {
+		decoded, _ := DecodeKMer(kmer, 3)
+		positions = append(positions, pos)
+		kmers = append(kmers, decoded)
+	})
+	if err != nil {
+		t.Fatalf("ForEachKMer() error = %v", err)
+	}
+
+	// k-mers overlapping N at position 4 are skipped
+	if !reflect.DeepEqual(positions, []int{0, 1, 5, 6}) {
+		t.Errorf("unexpected k-mer positions %v", positions)
+	}
+	if !reflect.DeepEqual(kmers, []DNASequence{"ACG", "CGT", "ACG", "CGA"}) {
+		t.Errorf("unexpected k-mers %v", kmers)
+	}
+
+	if kmer, ok := packed.KMer(1, 3); !ok || kmer != 0b011011 {
+		t.Errorf("KMer(1, 3) got = %b, %v", kmer, ok)
+	}
+	if _, ok := packed.KMer(3, 3); ok {
+		t.Errorf("KMer() expected ambiguous k-mer to be rejected")
+	}
+	if err = packed.ForEachKMer(33, func(int, uint64) {}); err != ErrInvalidKMerSize {
+		t.Errorf("expected ErrInvalidKMerSize, got %v", err)
+	}
+}
+
+func TestPackDNA_SparseAmbiguity(t *testing.T) {
+	genome := []byte(syntheticGenome(1_000_000))
+	// a sequencing gap and a few scattered ambiguous bases
+	copy(genome[400_000:], strings.Repeat("N", 100))
+	genome[10] = 'R'
+	genome[700_001] = 'n'
+	genome[999_999] = '-'
+
+	packed, err := PackDNA(DNASequence(genome))
+	if err != nil {
+		t.Fatalf("PackDNA() error = %v", err)
+	}
+
+	size := len(packed.data) + len(packed.ambiguous)*int(unsafe.Sizeof(ambiguousRun{}))
+	if len(packed.data) != len(genome)/4 || size > len(genome)/4+200 {
+		t.Errorf("expected about %d bytes for %d bases with 4 ambiguous runs, got %d bytes and %d runs",
+			len(genome)/4, len(genome), size, len(packed.ambiguous))
+	}
+	if got := packed.DNA(); got != DNASequence(strings.ToUpper(string(genome))) {
+		t.Errorf("DNA() does not round-trip the genome")
+	}
+
+	// slices see the runs overlapping them only
+	slice := packed.Slice(399_990, 400_110)
+	expected, _ := PackDNA(DNASequence(genome[399_990:400_110]))
+	if !slice.Ambiguous() || packed.Slice(0, 10).Ambiguous() {
+		t.Errorf("Ambiguous() must only report runs within the slice")
+	}
+	if slice.Complement().DNA() != expected.Complement().DNA() {
+		t.Errorf("Complement() of the slice differs from the packed substring")
+	}
+	if slice.ReverseComplement().DNA() != expected.ReverseComplement().DNA() {
+		t.Errorf("ReverseComplement() of the slice differs from the packed substring")
+	}
+
+	var got, want []int
+	_ = slice.ForEachKMer(5, func(pos int, _ uint64) { got = append(got, pos) })
+	_ = expected.ForEachKMer(5, func(pos int, _ uint64) { want = append(want, pos) })
+	if !reflect.DeepEqual(got, want) || len(got) != 12 {
+		t.Errorf("ForEachKMer() positions %v, expected %v", got, want)
+	}
+}
+
+func BenchmarkPackedSequence_ReverseComplement(b *testing.B) {
+	packed, _ := PackDNA(syntheticGenome(2_000_000))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		packed.ReverseComplement()
+	}
+}
+
+func BenchmarkPackedSequence_Complement(b *testing.B) {
+	packed, _ := PackDNA(syntheticGenome(2_000_000))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		packed.Complement()
+	}
+}
+
+func TestPackedSequence_ReverseComplement_Slices(t *testing.T) {
+	for _, input := range []DNASequence{"ATGCCCTAAGGTACCA", "ATGNCCTARGGTACCA"} {
+		packed, err := PackDNA(input)
+		if err != nil {
+			t.Fatalf("PackDNA() error = %v", err)
+		}
+
+		for start := 0; start <= len(input); start++ {
+			for end := start; end <= len(input); end++ {
+				slice := packed.Slice(start, end)
+				expected := slice.Complement().DNA().Reverse()
+				if got := slice.ReverseComplement().DNA(); got != expected {
+					t.Errorf("ReverseComplement() of %s[%d:%d] got = %s, expected %s", input, start, end, got, expected)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package sequence
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrInvalidWindow = errors.New("window size must be positive and step must not be negative")
+
+// WindowOptions configure sliding-window profiles.
+type WindowOptions struct {
+	Size int
+	// Step is the distance between the starts of consecutive windows, it defaults to Size.
+	Step int
+}
+
+func (o WindowOptions) validate() (WindowOptions, error) {
+	if o.Size < 1 || o.Step < 0 {
+		return o, ErrInvalidWindow
+	}
+	if o.Step == 0 {
+		o.Step = o.Size
+	}
+	return o, nil
+}
+
+// slidingWindow keeps the last Size letters of a stream.
+type slidingWindow struct {
+	WindowOptions
+	ring []byte
+	pos  int // number of letters pushed
+}
+
+// push adds a letter and returns the letter leaving the window, false while the window fills up
+func (w *slidingWindow) push(letter byte) (byte, bool) {
+	i := w.pos % w.Size
+	old, evicted := w.ring[i], w.pos >= w.Size
+
+	w.ring[i] = letter
+	w.pos++
+
+	return old, evicted
+}
+
+// complete reports whether the window ending at the last letter starts at a step
+func (w *slidingWindow) complete() bool {
+	return w.pos >= w.Size && (w.pos-w.Size)%w.Step == 0
+}
+
+func (w *slidingWindow) interval() Interval {
+	if w.pos < w.Size {
+		return Interval{Start: 0, End: w.pos}
+	}
+	return Interval{Start: w.pos - w.Size, End: w.pos}
+}
+
+// short reports whether the stream ended before the first window was complete
+func (w *slidingWindow) short() bool {
+	return w.pos > 0 && w.pos < w.Size
+}
+
+func isWhitespace(letter byte) bool {
+	return letter == '\n' || letter == '\r' || letter == ' ' || letter == '\t'
+}
+
+func upper(letter byte) byte {
+	if letter >= 'a' && letter <= 'z' {
+		return letter - 'a' + 'A'
+	}
+	return letter
+}
+
+// NucleotideWindow is the composition of a window of a DNA or RNA sequence. GC content and skews only count
+// unambiguous bases, U is counted as T. The cumulative skews add up the skews of the bases read since the previous
+// window, so bases shared by overlapping windows are counted once and the result doesn't depend on the step;
+// in bacterial genomes the minimum and maximum of the cumulative GC skew point to the origin and the terminus
+// of replication.
+type NucleotideWindow struct {
+	Interval
+	GCContent        float64
+	GCSkew           float64 // (G-C)/(G+C)
+	ATSkew           float64 // (A-T)/(A+T)
+	CumulativeGCSkew float64
+	CumulativeATSkew float64
+}
+
+// NucleotideProfiler computes a NucleotideWindow for every window of a sequence written to it in chunks of any
+// size, so chromosome-scale sequences never have to be held in memory. Whitespace, e.g. line breaks of a FASTA
+// file, is skipped.
+type NucleotideProfiler struct {
+	window       slidingWindow
+	counts       [256]int // number of every uppercase letter in the window
+	stepCounts   [256]int // number of every uppercase letter read since the previous window
+	cumulativeGC float64
+	cumulativeAT float64
+	closed       bool
+	emit         func(NucleotideWindow)
+}
+
+func NewNucleotideProfiler(options WindowOptions, emit func(NucleotideWindow)) (*NucleotideProfiler, error) {
+	options, err := options.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NucleotideProfiler{
+		window: slidingWindow{WindowOptions: options, ring: make([]byte, options.Size)},
+		emit:   emit,
+	}, nil
+}
+
+// Write returns io.ErrClosedPipe after Close.
+func (p *NucleotideProfiler) Write(data []byte) (int, error) {
+	if p.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	for _, letter := range data {
+		if isWhitespace(letter) {
+			continue
+		}
+
+		letter = upper(letter)
+		if letter == 'U' {
+			letter = 'T'
+		}
+
+		old, evicted := p.window.push(letter)
+		if evicted {
+			p.counts[old]--
+		}
+		p.counts[letter]++
+		p.stepCounts[letter]++
+
+		if p.window.complete() {
+			p.emitWindow()
+		}
+	}
+
+	return len(data), nil
+}
+
+// Close reports a single window over the whole sequence if it was shorter than the window size.
+// Closing twice reports nothing more.
+func (p *NucleotideProfiler) Close() error {
+	if !p.closed && p.window.short() {
+		p.emitWindow()
+	}
+	p.closed = true
+	return nil
+}
+
+func (p *NucleotideProfiler) emitWindow() {
+	a, c, g, t := float64(p.counts['A']), float64(p.counts['C']), float64(p.counts['G']), float64(p.counts['T'])
+
+	window := NucleotideWindow{
+		Interval:  p.window.interval(),
+		GCContent: ratio(g+c, a+c+g+t),
+		GCSkew:    ratio(g-c, g+c),
+		ATSkew:    ratio(a-t, a+t),
+	}
+
+	a, c, g, t = float64(p.stepCounts['A']), float64(p.stepCounts['C']), float64(p.stepCounts['G']), float64(p.stepCounts['T'])
+	p.cumulativeGC += ratio(g-c, g+c)
+	p.cumulativeAT += ratio(a-t, a+t)
+	window.CumulativeGCSkew, window.CumulativeATSkew = p.cumulativeGC, p.cumulativeAT
+	p.stepCounts = [256]int{}
+
+	p.emit(window)
+}
+
+func ratio(numerator, denominator float64) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return numerator / denominator
+}
+
+// NucleotideProfile returns the windows of a sequence held in memory, see NucleotideProfiler.
+func NucleotideProfile[S NucleotideSequence](seq S, options WindowOptions) ([]NucleotideWindow, error) {
+	var windows []NucleotideWindow
+	profiler, err := NewNucleotideProfiler(options, func(window NucleotideWindow) {
+		windows = append(windows, window)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = profiler.Write([]byte(seq.String()))
+	return windows, profiler.Close()
+}
+
+// HydropathyWindow is the mean hydropathy of the residues of a window that have a value in the scale,
+// e.g. stops, gaps and ambiguous residues are left out.
+type HydropathyWindow struct {
+	Interval
+	Hydropathy float64
+}
+
+// HydropathyProfiler computes a HydropathyWindow for every window of a protein written to it in chunks.
+// Whitespace is skipped.
+type HydropathyProfiler struct {
+	window slidingWindow
+	values [256]float64
+	known  [256]bool
+	sum    float64
+	n      int // number of residues with a value in the window
+	closed bool
+	emit   func(HydropathyWindow)
+}
+
+func NewHydropathyProfiler(scale HydropathyScale, options WindowOptions, emit func(HydropathyWindow)) (*HydropathyProfiler, error) {
+	options, err := options.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	profiler := &HydropathyProfiler{
+		window: slidingWindow{WindowOptions: options, ring: make([]byte, options.Size)},
+		emit:   emit,
+	}
+	for residue, value := range scale {
+		letter := upper(byte(residue))
+		profiler.values[letter], profiler.known[letter] = value, true
+	}
+
+	return profiler, nil
+}
+
+// Write returns io.ErrClosedPipe after Close.
+func (p *HydropathyProfiler) Write(data []byte) (int, error) {
+	if p.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	for _, letter := range data {
+		if isWhitespace(letter) {
+			continue
+		}
+
+		letter = upper(letter)
+		old, evicted := p.window.push(letter)
+		if evicted && p.known[old] {
+			p.sum -= p.values[old]
+			p.n--
+		}
+		if p.known[letter] {
+			p.sum += p.values[letter]
+			p.n++
+		}
+
+		if p.window.complete() {
+			p.emitWindow()
+		}
+	}
+
+	return len(data), nil
+}
+
+// Close reports a single window over the whole protein if it was shorter than the window size.
+// Closing twice reports nothing more.
+func (p *HydropathyProfiler) Close() error {
+	if !p.closed && p.window.short() {
+		p.emitWindow()
+	}
+	p.closed = true
+	return nil
+}
+
+func (p *HydropathyProfiler) emitWindow() {
+	p.emit(HydropathyWindow{
+		Interval:   p.window.interval(),
+		Hydropathy: ratio(p.sum, float64(p.n)),
+	})
+}
+
+// HydropathyProfile returns the hydropathy windows of p, e.g. with KyteDoolittle and a window of 19 residues
+// peaks above 1.6 suggest transmembrane helices.
+func (p ProteinSequence) HydropathyProfile(scale HydropathyScale, options WindowOptions) ([]HydropathyWindow, error) {
+	var windows []HydropathyWindow
+	profiler, err := NewHydropathyProfiler(scale, options, func(window HydropathyWindow) {
+		windows = append(windows, window)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = profiler.Write([]byte(p))
+	return windows, profiler.Close()
+}
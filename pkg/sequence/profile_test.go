@@ -0,0 +1,172 @@
+package sequence
+
+import (
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNucleotideProfile(t *testing.T) {
+	windows, err := NucleotideProfile(DNASequence("GGGCAAAT"+"ccccNNTT"), WindowOptions{Size: 8})
+	if err != nil {
+		t.Fatalf("NucleotideProfile() error = %v", err)
+	}
+
+	expected := []NucleotideWindow{
+		{
+			Interval:         Interval{Start: 0, End: 8},
+			GCContent:        0.5,
+			GCSkew:           0.5,
+			ATSkew:           0.5,
+			CumulativeGCSkew: 0.5,
+			CumulativeATSkew: 0.5,
+		},
+		{
+			Interval:         Interval{Start: 8, End: 16},
+			GCContent:        4 / 6.,
+			GCSkew:           -1,
+			ATSkew:           -1,
+			CumulativeGCSkew: -0.5,
+			CumulativeATSkew: -0.5,
+		},
+	}
+	if !reflect.DeepEqual(windows, expected) {
+		t.Errorf("NucleotideProfile() = %+v, expected %+v", windows, expected)
+	}
+
+	windows, err = NucleotideProfile(RNASequence("GCAU"), WindowOptions{Size: 2, Step: 1})
+	if err != nil {
+		t.Fatalf("NucleotideProfile() error = %v", err)
+	}
+	if len(windows) != 3 || windows[2].Interval != (Interval{Start: 2, End: 4}) || windows[2].ATSkew != 0 {
+		t.Errorf("unexpected overlapping windows %+v", windows)
+	}
+
+	// overlapping windows add up the skew of every step once: GGGG, then CC, CC and AA
+	windows, err = NucleotideProfile(DNASequence("GGGGCCCCAA"), WindowOptions{Size: 4, Step: 2})
+	if err != nil {
+		t.Fatalf("NucleotideProfile() error = %v", err)
+	}
+	var cumulative []float64
+	for _, window := range windows {
+		cumulative = append(cumulative, window.CumulativeGCSkew)
+	}
+	if !reflect.DeepEqual(cumulative, []float64{1, 0, -1, -1}) {
+		t.Errorf("expected cumulative GC skews [1 0 -1 -1], got %v", cumulative)
+	}
+
+	windows, _ = NucleotideProfile(DNASequence("GGA"), WindowOptions{Size: 10})
+	if len(windows) != 1 || windows[0].Interval != (Interval{Start: 0, End: 3}) || windows[0].GCContent != 2/3. {
+		t.Errorf("expected a single window for a short sequence, got %+v", windows)
+	}
+
+	if _, err = NucleotideProfile(DNASequence("GGA"), WindowOptions{}); !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("expected ErrInvalidWindow, got %v", err)
+	}
+}
+
+func TestNucleotideProfiler_Stream(t *testing.T) {
+	genome := string(syntheticGenome(10_000))
+	options := WindowOptions{Size: 500, Step: 100}
+
+	expected, err := NucleotideProfile(DNASequence(genome), options)
+	if err != nil {
+		t.Fatalf("NucleotideProfile() error = %v", err)
+	}
+
+	var streamed []NucleotideWindow
+	profiler, err := NewNucleotideProfiler(options, func(window NucleotideWindow) {
+		streamed = append(streamed, window)
+	})
+	if err != nil {
+		t.Fatalf("NewNucleotideProfiler() error = %v", err)
+	}
+
+	// FASTA-like lines of 60 bases
+	for i := 0; i < len(genome); i += 60 {
+		end := i + 60
+		if end > len(genome) {
+			end = len(genome)
+		}
+		_, _ = profiler.Write([]byte(genome[i:end] + "\n"))
+	}
+	_ = profiler.Close()
+
+	if len(streamed) != (10_000-500)/100+1 {
+		t.Errorf("expected %d windows, got %d", (10_000-500)/100+1, len(streamed))
+	}
+	for i := range expected {
+		if streamed[i].Interval != expected[i].Interval || math.Abs(streamed[i].GCContent-expected[i].GCContent) > 1e-12 ||
+			math.Abs(streamed[i].CumulativeGCSkew-expected[i].CumulativeGCSkew) > 1e-9 {
+			t.Fatalf("streamed window %d = %+v, expected %+v", i, streamed[i], expected[i])
+		}
+	}
+}
+
+func TestProfiler_CloseTwice(t *testing.T) {
+	var nucleotideWindows []NucleotideWindow
+	nucleotideProfiler, _ := NewNucleotideProfiler(WindowOptions{Size: 10}, func(window NucleotideWindow) {
+		nucleotideWindows = append(nucleotideWindows, window)
+	})
+	_, _ = nucleotideProfiler.Write([]byte("ACGT"))
+	_ = nucleotideProfiler.Close()
+	_ = nucleotideProfiler.Close()
+	if _, err := nucleotideProfiler.Write([]byte("ACGT")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected io.ErrClosedPipe after Close, got %v", err)
+	}
+
+	if len(nucleotideWindows) != 1 || nucleotideWindows[0].Interval != (Interval{Start: 0, End: 4}) {
+		t.Errorf("expected a single short window, got %+v", nucleotideWindows)
+	}
+
+	var hydropathyWindows []HydropathyWindow
+	hydropathyProfiler, _ := NewHydropathyProfiler(KyteDoolittle, WindowOptions{Size: 10}, func(window HydropathyWindow) {
+		hydropathyWindows = append(hydropathyWindows, window)
+	})
+	_, _ = hydropathyProfiler.Write([]byte("IVLA"))
+	_ = hydropathyProfiler.Close()
+	_ = hydropathyProfiler.Close()
+	if _, err := hydropathyProfiler.Write([]byte("IVLA")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected io.ErrClosedPipe after Close, got %v", err)
+	}
+
+	if len(hydropathyWindows) != 1 {
+		t.Errorf("expected a single short window, got %+v", hydropathyWindows)
+	}
+}
+
+func TestProteinSequence_HydropathyProfile(t *testing.T) {
+	protein := ProteinSequence("IIVV" + "*KKRR")
+
+	windows, err := protein.HydropathyProfile(KyteDoolittle, WindowOptions{Size: 4, Step: 5})
+	if err != nil {
+		t.Fatalf("HydropathyProfile() error = %v", err)
+	}
+
+	expected := []HydropathyWindow{
+		{Interval: Interval{Start: 0, End: 4}, Hydropathy: (4.5 + 4.5 + 4.2 + 4.2) / 4},
+		{Interval: Interval{Start: 5, End: 9}, Hydropathy: (-3.9 - 3.9 - 4.5 - 4.5) / 4},
+	}
+	if len(windows) != len(expected) {
+		t.Fatalf("HydropathyProfile() = %+v, expected %+v", windows, expected)
+	}
+	for i := range expected {
+		if windows[i].Interval != expected[i].Interval || math.Abs(windows[i].Hydropathy-expected[i].Hydropathy) > 1e-9 {
+			t.Errorf("window %d = %+v, expected %+v", i, windows[i], expected[i])
+		}
+	}
+
+	// the stop has no value and is left out of the mean
+	windows, _ = protein.HydropathyProfile(HoppWoods, WindowOptions{Size: 2, Step: 1})
+	if window := windows[3]; window.Interval != (Interval{Start: 3, End: 5}) || math.Abs(window.Hydropathy-(-1.5)) > 1e-9 {
+		t.Errorf("unexpected window with a stop %+v", window)
+	}
+
+	windows, _ = ProteinSequence(strings.Repeat("L", 30)).HydropathyProfile(EisenbergConsensus, WindowOptions{Size: 19, Step: 1})
+	if len(windows) != 12 || math.Abs(windows[11].Hydropathy-1.06) > 1e-9 {
+		t.Errorf("unexpected profile of poly-Leu %+v", windows)
+	}
+}
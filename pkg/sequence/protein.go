@@ -1,3 +1,29 @@
 package sequence
 
+import "strings"
+
 type ProteinSequence string
+
+// NewProteinSequence accepts amino acids, ambiguity codes, stops and gaps in any case
+// and returns the uppercase sequence.
+func NewProteinSequence(input string) (ProteinSequence, error) {
+	return NewProteinSequenceWithAlphabet(input, ProteinGappedAlphabet)
+}
+
+// NewProteinSequenceWithAlphabet returns an *InvalidCharacterError for characters missing from the alphabet.
+func NewProteinSequenceWithAlphabet(input string, alphabet *Alphabet) (ProteinSequence, error) {
+	err := alphabet.Validate(input)
+	if err != nil {
+		return "", err
+	}
+
+	return ProteinSequence(strings.ToUpper(input)), nil
+}
+
+func (p ProteinSequence) String() string {
+	return string(p)
+}
+
+func (p ProteinSequence) Length() int {
+	return len(p)
+}
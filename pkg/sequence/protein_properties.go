@@ -0,0 +1,281 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	ErrAmbiguousResidue = errors.New("ambiguous residue")
+	ErrUnknownResidue   = errors.New("no property value for residue")
+)
+
+// AmbiguityPolicy tells protein property calculations how to treat the ambiguous residues of AmbiguousAminoAcidsMap.
+type AmbiguityPolicy int
+
+const (
+	// RejectAmbiguous returns ErrAmbiguousResidue for the first ambiguous residue.
+	RejectAmbiguous AmbiguityPolicy = iota
+	// SkipAmbiguous leaves ambiguous residues out, as if they were not in the sequence.
+	SkipAmbiguous
+	// AverageAmbiguous counts an ambiguous residue as each of its possible residues with equal weight,
+	// e.g. B as half N and half D.
+	AverageAmbiguous
+)
+
+type MassType int
+
+const (
+	AverageMass MassType = iota
+	MonoisotopicMass
+)
+
+// residues returns the possible residues of every position of p, stops and gaps are left out
+func (p ProteinSequence) residues(policy AmbiguityPolicy) ([][]AminoAcid, error) {
+	residues := make([][]AminoAcid, 0, len(p))
+
+	for i, residue := range []AminoAcid(strings.ToUpper(string(p))) {
+		if residue == '*' || residue == '-' {
+			continue
+		}
+
+		possible, isAmbiguous := AmbiguousAminoAcidsMap[residue]
+		if !isAmbiguous {
+			residues = append(residues, []AminoAcid{residue})
+			continue
+		}
+
+		switch policy {
+		case SkipAmbiguous:
+		case AverageAmbiguous:
+			residues = append(residues, possible)
+		default:
+			return nil, fmt.Errorf("%w %q at position %d", ErrAmbiguousResidue, residue, i)
+		}
+	}
+
+	return residues, nil
+}
+
+// residueCounts returns the number of every residue, ambiguous positions are split between their residues
+func residueCounts(residues [][]AminoAcid) map[AminoAcid]float64 {
+	counts := make(map[AminoAcid]float64)
+	for _, possible := range residues {
+		for _, residue := range possible {
+			counts[residue] += 1 / float64(len(possible))
+		}
+	}
+
+	return counts
+}
+
+// meanValue returns the mean value of the possible residues of a position
+func meanValue(possible []AminoAcid, values map[AminoAcid]float64) (float64, error) {
+	sum := 0.
+	for _, residue := range possible {
+		value, ok := values[residue]
+		if !ok {
+			return 0, fmt.Errorf("%w %q", ErrUnknownResidue, residue)
+		}
+		sum += value
+	}
+
+	return sum / float64(len(possible)), nil
+}
+
+// sumValues returns the sum of the values of all positions
+func sumValues(residues [][]AminoAcid, values map[AminoAcid]float64) (float64, error) {
+	sum := 0.
+	for _, possible := range residues {
+		value, err := meanValue(possible, values)
+		if err != nil {
+			return 0, err
+		}
+		sum += value
+	}
+
+	return sum, nil
+}
+
+// MolecularWeight returns the mass of the unmodified protein in Da. Stops and gaps are ignored.
+func (p ProteinSequence) MolecularWeight(massType MassType, policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil || len(residues) == 0 {
+		return 0, err
+	}
+
+	masses, water := averageResidueMasses, averageWaterMass
+	if massType == MonoisotopicMass {
+		masses, water = monoisotopicResidueMasses, monoisotopicWaterMass
+	}
+
+	mass, err := sumValues(residues, masses)
+	if err != nil {
+		return 0, err
+	}
+
+	return mass + water, nil
+}
+
+// Charge returns the net charge of the protein at the given pH, from the pK values of the charged side chains
+// and of the termini (Bjellqvist et al., 1993).
+func (p ProteinSequence) Charge(pH float64, policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil || len(residues) == 0 {
+		return 0, err
+	}
+
+	return charge(residues, residueCounts(residues), pH), nil
+}
+
+func charge(residues [][]AminoAcid, counts map[AminoAcid]float64, pH float64) float64 {
+	positive := func(pK float64) float64 { return 1 / (math.Pow(10, pH-pK) + 1) }
+	negative := func(pK float64) float64 { return 1 / (math.Pow(10, pK-pH) + 1) }
+
+	total := 0.
+	for residue, pK := range positivePKs {
+		total += counts[residue] * positive(pK)
+	}
+	for residue, pK := range negativePKs {
+		total -= counts[residue] * negative(pK)
+	}
+
+	// the pK values of the termini depend on the terminal residues
+	nTerminal, cTerminal := residues[0], residues[len(residues)-1]
+	for _, residue := range nTerminal {
+		pK, ok := nTerminalPKs[residue]
+		if !ok {
+			pK = nTerminalPK
+		}
+		total += positive(pK) / float64(len(nTerminal))
+	}
+	for _, residue := range cTerminal {
+		pK, ok := cTerminalPKs[residue]
+		if !ok {
+			pK = cTerminalPK
+		}
+		total -= negative(pK) / float64(len(cTerminal))
+	}
+
+	return total
+}
+
+// IsoelectricPoint returns the pH at which the net charge of the protein is 0, with a precision of 0.0001.
+func (p ProteinSequence) IsoelectricPoint(policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil || len(residues) == 0 {
+		return 0, err
+	}
+
+	counts := residueCounts(residues)
+
+	// the charge decreases monotonically with the pH
+	low, high := 0., 14.
+	for high-low > 0.0001 {
+		middle := (low + high) / 2
+		if charge(residues, counts, middle) > 0 {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+
+	return (low + high) / 2, nil
+}
+
+// ExtinctionCoefficient returns the molar extinction coefficient at 280 nm in water in M^-1 cm^-1
+// (Pace et al., 1995). With cystines set all cysteines are assumed to form disulfide bonds.
+func (p ProteinSequence) ExtinctionCoefficient(cystines bool, policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil {
+		return 0, err
+	}
+
+	counts := residueCounts(residues)
+	extinction := counts['W']*tryptophanExtinction + counts['Y']*tyrosineExtinction
+	if cystines {
+		extinction += math.Floor(counts['C']/2) * cystineExtinction
+	}
+
+	return extinction, nil
+}
+
+// InstabilityIndex estimates the stability of the protein in a test tube from its dipeptides (Guruprasad et al.,
+// 1990). Proteins with an index above 40 are predicted as unstable.
+func (p ProteinSequence) InstabilityIndex(policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil || len(residues) == 0 {
+		return 0, err
+	}
+
+	sum := 0.
+	for i := 0; i+1 < len(residues); i++ {
+		first, second := residues[i], residues[i+1]
+
+		dipeptide := 0.
+		for _, residue := range first {
+			weights, ok := diwv[residue]
+			if !ok {
+				return 0, fmt.Errorf("%w %q", ErrUnknownResidue, residue)
+			}
+
+			weight, err := meanValue(second, weights)
+			if err != nil {
+				return 0, err
+			}
+			dipeptide += weight / float64(len(first))
+		}
+
+		sum += dipeptide
+	}
+
+	return 10 / float64(len(residues)) * sum, nil
+}
+
+// AliphaticIndex returns the relative volume occupied by aliphatic side chains (Ikai, 1980), a positive
+// factor for the thermostability of globular proteins.
+func (p ProteinSequence) AliphaticIndex(policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil || len(residues) == 0 {
+		return 0, err
+	}
+
+	counts := residueCounts(residues)
+	percent := func(residue AminoAcid) float64 {
+		return counts[residue] * 100 / float64(len(residues))
+	}
+
+	return percent('A') + 2.9*percent('V') + 3.9*(percent('I')+percent('L')), nil
+}
+
+// GRAVY returns the grand average of hydropathy: the mean Kyte-Doolittle hydropathy of the residues.
+func (p ProteinSequence) GRAVY(policy AmbiguityPolicy) (float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil || len(residues) == 0 {
+		return 0, err
+	}
+
+	sum, err := sumValues(residues, KyteDoolittle)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum / float64(len(residues)), nil
+}
+
+// Composition returns the fraction of every residue in the protein, stops and gaps are not counted.
+func (p ProteinSequence) Composition(policy AmbiguityPolicy) (map[AminoAcid]float64, error) {
+	residues, err := p.residues(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	composition := residueCounts(residues)
+	for residue := range composition {
+		composition[residue] /= float64(len(residues))
+	}
+
+	return composition, nil
+}
@@ -0,0 +1,143 @@
+package sequence
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestProteinSequence_MolecularWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		protein  ProteinSequence
+		massType MassType
+		policy   AmbiguityPolicy
+		expected float64
+		wantErr  error
+	}{
+		{name: "glycine", protein: "G", massType: AverageMass, expected: 75.06714},
+		{name: "glycine-monoisotopic", protein: "G", massType: MonoisotopicMass, expected: 57.02146 + 18.01056},
+		{name: "stops-and-gaps", protein: "a-G*", massType: AverageMass, expected: 71.0788 + 57.0519 + 18.01524},
+		{name: "empty", protein: "*", massType: AverageMass, expected: 0},
+		{name: "reject", protein: "GB", massType: AverageMass, wantErr: ErrAmbiguousResidue},
+		{name: "skip", protein: "GB", massType: AverageMass, policy: SkipAmbiguous, expected: 75.06714},
+		{
+			name:     "average",
+			protein:  "B",
+			massType: AverageMass,
+			policy:   AverageAmbiguous,
+			expected: (114.1038+115.0886)/2 + 18.01524,
+		},
+		{name: "unknown", protein: "G1", massType: AverageMass, wantErr: ErrUnknownResidue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.protein.MolecularWeight(tt.massType, tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MolecularWeight() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("MolecularWeight() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProteinSequence_Charge(t *testing.T) {
+	acidic, err := ProteinSequence("DDEEDDEE").IsoelectricPoint(RejectAmbiguous)
+	if err != nil || acidic > 4 {
+		t.Errorf("IsoelectricPoint() of acidic protein = %v, %v", acidic, err)
+	}
+
+	basic, err := ProteinSequence("KKRRKKRR").IsoelectricPoint(RejectAmbiguous)
+	if err != nil || basic < 10 {
+		t.Errorf("IsoelectricPoint() of basic protein = %v, %v", basic, err)
+	}
+
+	protein := ProteinSequence("MKTAYIAKQRQISFVKSHFSRQDEEC")
+	pI, err := protein.IsoelectricPoint(RejectAmbiguous)
+	if err != nil {
+		t.Fatalf("IsoelectricPoint() error = %v", err)
+	}
+	if charge, _ := protein.Charge(pI, RejectAmbiguous); math.Abs(charge) > 1e-3 {
+		t.Errorf("Charge() at the isoelectric point = %v, expected 0", charge)
+	}
+	if charge, _ := protein.Charge(pI-1, RejectAmbiguous); charge <= 0 {
+		t.Errorf("Charge() below the isoelectric point = %v, expected positive", charge)
+	}
+
+	// at very low pH every basic group and the N-terminus are protonated
+	if charge, _ := ProteinSequence("GKHRG").Charge(-10, RejectAmbiguous); math.Abs(charge-4) > 1e-6 {
+		t.Errorf("Charge() at pH -10 = %v, expected 4", charge)
+	}
+
+	// B is half D and half N
+	averaged, _ := ProteinSequence("GBG").Charge(7, AverageAmbiguous)
+	withD, _ := ProteinSequence("GDG").Charge(7, RejectAmbiguous)
+	withN, _ := ProteinSequence("GNG").Charge(7, RejectAmbiguous)
+	if math.Abs(averaged-(withD+withN)/2) > 1e-9 {
+		t.Errorf("Charge() of B = %v, expected %v", averaged, (withD+withN)/2)
+	}
+}
+
+func TestProteinSequence_Indices(t *testing.T) {
+	protein := ProteinSequence("WWYCCAR")
+
+	extinction, err := protein.ExtinctionCoefficient(true, RejectAmbiguous)
+	if err != nil || extinction != 2*5500+1490+125 {
+		t.Errorf("ExtinctionCoefficient() with cystines = %v, %v", extinction, err)
+	}
+	extinction, err = protein.ExtinctionCoefficient(false, RejectAmbiguous)
+	if err != nil || extinction != 2*5500+1490 {
+		t.Errorf("ExtinctionCoefficient() with reduced cysteines = %v, %v", extinction, err)
+	}
+
+	gravy, err := ProteinSequence("AR*").GRAVY(RejectAmbiguous)
+	if err != nil || math.Abs(gravy-(-1.35)) > 1e-9 {
+		t.Errorf("GRAVY() = %v, %v, expected -1.35", gravy, err)
+	}
+	if _, err = ProteinSequence("AU").GRAVY(RejectAmbiguous); !errors.Is(err, ErrUnknownResidue) {
+		t.Errorf("expected ErrUnknownResidue for selenocysteine, got %v", err)
+	}
+
+	aliphatic, err := ProteinSequence("AVIL").AliphaticIndex(RejectAmbiguous)
+	if err != nil || math.Abs(aliphatic-292.5) > 1e-9 {
+		t.Errorf("AliphaticIndex() = %v, %v, expected 292.5", aliphatic, err)
+	}
+	aliphatic, err = ProteinSequence("JG").AliphaticIndex(AverageAmbiguous)
+	if err != nil || math.Abs(aliphatic-3.9*50) > 1e-9 {
+		t.Errorf("AliphaticIndex() of J = %v, %v, expected 195", aliphatic, err)
+	}
+
+	instability, err := ProteinSequence("ACM").InstabilityIndex(RejectAmbiguous)
+	if expected := 10 / 3. * (44.94 + 33.60); err != nil || math.Abs(instability-expected) > 1e-9 {
+		t.Errorf("InstabilityIndex() = %v, %v, expected %v", instability, err, expected)
+	}
+	instability, err = ProteinSequence("AZ").InstabilityIndex(AverageAmbiguous)
+	if expected := 10 / 2. * (1.0 + 1.0) / 2; err != nil || math.Abs(instability-expected) > 1e-9 {
+		t.Errorf("InstabilityIndex() of Z = %v, %v, expected %v", instability, err, expected)
+	}
+}
+
+func TestProteinSequence_Composition(t *testing.T) {
+	composition, err := ProteinSequence("AABX*").Composition(SkipAmbiguous)
+	if err != nil || len(composition) != 1 || composition['A'] != 1 {
+		t.Errorf("Composition() skipping ambiguous residues = %v, %v", composition, err)
+	}
+
+	composition, err = ProteinSequence("AABD").Composition(AverageAmbiguous)
+	expected := map[AminoAcid]float64{'A': 0.5, 'N': 0.125, 'D': 0.375}
+	if err != nil || len(composition) != len(expected) {
+		t.Fatalf("Composition() = %v, %v", composition, err)
+	}
+	for residue, fraction := range expected {
+		if math.Abs(composition[residue]-fraction) > 1e-9 {
+			t.Errorf("Composition()[%c] = %v, expected %v", residue, composition[residue], fraction)
+		}
+	}
+
+	if _, err = ProteinSequence("AX").Composition(RejectAmbiguous); !errors.Is(err, ErrAmbiguousResidue) {
+		t.Errorf("expected ErrAmbiguousResidue, got %v", err)
+	}
+}
@@ -0,0 +1,465 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// firstCustomTableID is the lowest ID given to derived tables registered without an explicit ID.
+const firstCustomTableID = 1000
+
+// builtinAliases are short names for the NCBI tables, registered in addition to the normalized table names.
+var builtinAliases = map[int][]string{
+	1:  {"standard"},
+	2:  {"mito-vert"},
+	3:  {"mito-yeast"},
+	4:  {"mito-mold", "mycoplasma"},
+	5:  {"mito-invert"},
+	6:  {"ciliate"},
+	9:  {"mito-echinoderm", "mito-flatworm"},
+	10: {"euplotid"},
+	11: {"bacterial", "plastid"},
+	12: {"yeast-alt"},
+	13: {"mito-ascidian"},
+	14: {"mito-flatworm-alt"},
+	15: {"blepharisma"},
+	16: {"mito-chlorophycean"},
+	21: {"mito-trematode"},
+	22: {"mito-scenedesmus"},
+	23: {"mito-thraustochytrium"},
+	24: {"mito-rhabdopleuridae"},
+	25: {"sr1", "gracilibacteria"},
+	26: {"pachysolen"},
+	27: {"karyorelict"},
+	28: {"condylostoma"},
+	29: {"mesodinium"},
+	30: {"peritrich"},
+	31: {"blastocrithidia"},
+	32: {"plastid-balanophoraceae"},
+	33: {"mito-cephalodiscidae"},
+}
+
+// CodonTableRegistry holds built-in and custom codon tables addressable by ID, name or alias.
+// It is safe for concurrent use.
+type CodonTableRegistry struct {
+	mu      sync.RWMutex
+	tables  []CodonTable
+	aliases map[string]int // normalized alias -> table ID
+}
+
+// DerivedCodonTable describes a custom table built from an already registered one.
+type DerivedCodonTable struct {
+	BaseID int
+	// ID of the new table, it must not be in use. The next free ID from 1000 on is used when zero.
+	ID      int
+	Name    string
+	Aliases []string
+	// Codons reassigned in the derived table. Assigning '*' turns a codon into a stop codon.
+	Codons map[string]AminoAcid
+	// StartCodons replace the start codons of the base table when not nil.
+	StartCodons []string
+}
+
+var defaultRegistry = NewCodonTableRegistry()
+
+// NewCodonTableRegistry returns a registry with the built-in NCBI tables.
+func NewCodonTableRegistry() *CodonTableRegistry {
+	r := &CodonTableRegistry{}
+	r.Reset()
+	return r
+}
+
+func GetCodonTable(id int) (CodonTable, error) {
+	return defaultRegistry.Get(id)
+}
+
+func LookupCodonTable(nameOrAlias string) (CodonTable, error) {
+	return defaultRegistry.Lookup(nameOrAlias)
+}
+
+func CodonTables() []CodonTable {
+	return defaultRegistry.Tables()
+}
+
+func CodonTableAliases(id int) []string {
+	return defaultRegistry.Aliases(id)
+}
+
+// RegisterCodonTables adds tables alongside the active ones. Tables with an already known ID or a name taken by
+// another table are rejected, use ReplaceCodonTables to overwrite the active tables.
+func RegisterCodonTables(tables ...CodonTable) error {
+	return defaultRegistry.Register(tables...)
+}
+
+func RegisterDerivedCodonTable(derived DerivedCodonTable) (CodonTable, error) {
+	return defaultRegistry.RegisterDerived(derived)
+}
+
+func AddCodonTableAlias(alias string, id int) error {
+	return defaultRegistry.AddAlias(alias, id)
+}
+
+// ReplaceCodonTables makes tables the only ones available, instead of the built-in NCBI tables.
+func ReplaceCodonTables(tables ...CodonTable) error {
+	return defaultRegistry.Replace(tables...)
+}
+
+// ResetCodonTables restores the built-in NCBI tables and drops all custom tables and aliases.
+func ResetCodonTables() {
+	defaultRegistry.Reset()
+}
+
+func (r *CodonTableRegistry) Get(id int) (CodonTable, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.index(id)
+	if i < 0 {
+		return CodonTable{}, fmt.Errorf("codon table no. %d not found", id)
+	}
+
+	return r.tables[i].Copy(), nil
+}
+
+// Lookup finds a table by numeric ID, name or alias. Names are matched case-insensitively,
+// with spaces and punctuation treated as dashes, so "Vertebrate Mitochondrial" also matches "vertebrate-mitochondrial".
+func (r *CodonTableRegistry) Lookup(nameOrAlias string) (CodonTable, error) {
+	if id, err := strconv.Atoi(strings.TrimSpace(nameOrAlias)); err == nil {
+		return r.Get(id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.aliases[normalizeAlias(nameOrAlias)]
+	if !ok || r.index(id) < 0 {
+		return CodonTable{}, fmt.Errorf("codon table '%s' not found", nameOrAlias)
+	}
+
+	return r.tables[r.index(id)].Copy(), nil
+}
+
+// Tables lists built-in and custom tables in registration order.
+func (r *CodonTableRegistry) Tables() []CodonTable {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tables := make([]CodonTable, len(r.tables))
+	for i := range r.tables {
+		tables[i] = r.tables[i].Copy()
+	}
+
+	return tables
+}
+
+// Aliases returns the sorted names and aliases of the table with the given ID.
+func (r *CodonTableRegistry) Aliases(id int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var aliases []string
+	for alias, aliasID := range r.aliases {
+		if aliasID == id {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+
+	return aliases
+}
+
+func (r *CodonTableRegistry) Register(tables ...CodonTable) error {
+	copied, err := validatedCopies(tables)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err = r.checkNewTables(copied); err != nil {
+		return err
+	}
+
+	r.merge(copied)
+	return nil
+}
+
+func (r *CodonTableRegistry) RegisterDerived(derived DerivedCodonTable) (CodonTable, error) {
+	if strings.TrimSpace(derived.Name) == "" {
+		return CodonTable{}, errors.New("derived codon table must have a name")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	base := r.index(derived.BaseID)
+	if base < 0 {
+		return CodonTable{}, fmt.Errorf("base codon table no. %d not found", derived.BaseID)
+	}
+
+	if derived.ID != 0 && r.index(derived.ID) >= 0 {
+		return CodonTable{}, fmt.Errorf("codon table no. %d already exists", derived.ID)
+	}
+
+	table := r.tables[base].Copy()
+	table.ID = derived.ID
+	table.Name = derived.Name
+	table.Description = fmt.Sprintf("derived from table no. %d", derived.BaseID)
+
+	if table.ID == 0 {
+		table.ID = r.nextCustomID()
+	}
+	if table.StopCodons == nil {
+		table.StopCodons = make(map[string]AminoAcid)
+	}
+
+	err := validateCodonUsage(derived.Codons, true)
+	if err != nil {
+		return CodonTable{}, err
+	}
+
+	for codon, aa := range derived.Codons {
+		table.Codons[codon] = aa
+		if aa == '*' {
+			// a stop codon can't start translation
+			table.StopCodons[codon] = aa
+			delete(table.StartCodons, codon)
+		} else {
+			delete(table.StopCodons, codon)
+		}
+	}
+
+	if derived.StartCodons != nil {
+		table.StartCodons = make(map[string]AminoAcid, len(derived.StartCodons))
+		for _, codon := range derived.StartCodons {
+			aa, ok := table.Codons[codon]
+			if !ok {
+				return CodonTable{}, fmt.Errorf("unknown start codon '%s'", codon)
+			}
+			if aa == '*' {
+				return CodonTable{}, fmt.Errorf("start codon '%s' is a stop codon", codon)
+			}
+			table.StartCodons[codon] = aa
+		}
+	}
+
+	copied, err := validatedCopies([]CodonTable{table})
+	if err != nil {
+		return CodonTable{}, err
+	}
+
+	names := append(tableNames(table), derived.Aliases...)
+	if err = r.checkAliases(table.ID, names); err != nil {
+		return CodonTable{}, err
+	}
+
+	r.merge(copied)
+	for _, alias := range derived.Aliases {
+		r.aliases[normalizeAlias(alias)] = table.ID
+	}
+
+	return table.Copy(), nil
+}
+
+func (r *CodonTableRegistry) AddAlias(alias string, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index(id) < 0 {
+		return fmt.Errorf("codon table no. %d not found", id)
+	}
+	if err := r.checkAliases(id, []string{alias}); err != nil {
+		return err
+	}
+
+	r.aliases[normalizeAlias(alias)] = id
+	return nil
+}
+
+func (r *CodonTableRegistry) Replace(tables ...CodonTable) error {
+	copied, err := validatedCopies(tables)
+	if err != nil {
+		return err
+	}
+
+	// build the new state aside, so a rejected batch leaves the registry untouched
+	replacement := &CodonTableRegistry{aliases: make(map[string]int)}
+	if err = replacement.checkNewTables(copied); err != nil {
+		return err
+	}
+	replacement.merge(copied)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// aliases of the previous tables, including those added with AddAlias, are dropped with them
+	r.tables = replacement.tables
+	r.aliases = replacement.aliases
+
+	return nil
+}
+
+func (r *CodonTableRegistry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tables = nil
+	r.aliases = make(map[string]int)
+	r.merge(codonTables)
+
+	for id, aliases := range builtinAliases {
+		for _, alias := range aliases {
+			r.aliases[normalizeAlias(alias)] = id
+		}
+	}
+}
+
+// merge adds tables with new IDs and registers their names. The tables slice is never modified in place,
+// so the compiled-in codonTables can be shared safely.
+func (r *CodonTableRegistry) merge(tables []CodonTable) {
+	merged := make([]CodonTable, len(r.tables), len(r.tables)+len(tables))
+	copy(merged, r.tables)
+
+	for _, table := range tables {
+		merged = append(merged, table)
+
+		for _, name := range tableNames(table) {
+			if _, taken := r.aliases[normalizeAlias(name)]; !taken {
+				r.aliases[normalizeAlias(name)] = table.ID
+			}
+		}
+	}
+
+	r.tables = merged
+}
+
+// checkNewTables returns an error if any table ID is already registered or any table name is taken,
+// either by a registered table or by another table of the batch.
+func (r *CodonTableRegistry) checkNewTables(tables []CodonTable) error {
+	claimed := make(map[string]int)
+
+	for _, table := range tables {
+		if r.index(table.ID) >= 0 {
+			return fmt.Errorf("codon table no. %d already exists", table.ID)
+		}
+
+		names := tableNames(table)
+		if err := r.checkAliases(table.ID, names); err != nil {
+			return err
+		}
+		for _, name := range names {
+			normalized := normalizeAlias(name)
+			if other, taken := claimed[normalized]; taken && other != table.ID {
+				return fmt.Errorf("codon table alias '%s' is used by both table no. %d and no. %d", name, other, table.ID)
+			}
+			claimed[normalized] = table.ID
+		}
+	}
+
+	return nil
+}
+
+// checkAliases returns an error if any alias is invalid or already points to another table.
+func (r *CodonTableRegistry) checkAliases(id int, aliases []string) error {
+	for _, alias := range aliases {
+		normalized := normalizeAlias(alias)
+		if normalized == "" {
+			return fmt.Errorf("invalid codon table alias '%s'", alias)
+		}
+		if _, err := strconv.Atoi(normalized); err == nil {
+			return fmt.Errorf("invalid codon table alias '%s': numbers are reserved for table IDs", alias)
+		}
+		if other, taken := r.aliases[normalized]; taken && other != id && r.index(other) >= 0 {
+			return fmt.Errorf("codon table alias '%s' is already used by table no. %d", alias, other)
+		}
+	}
+
+	return nil
+}
+
+func (r *CodonTableRegistry) index(id int) int {
+	for i := range r.tables {
+		if r.tables[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (r *CodonTableRegistry) nextCustomID() int {
+	next := firstCustomTableID
+	for _, table := range r.tables {
+		if table.ID >= next {
+			next = table.ID + 1
+		}
+	}
+
+	return next
+}
+
+func tableNames(table CodonTable) []string {
+	names := []string{table.Name}
+	if table.Description != "" && !strings.Contains(table.Description, " ") {
+		// short descriptions such as "SGC0" are alternative names in gc.prt
+		names = append(names, table.Description)
+	}
+
+	return names
+}
+
+func normalizeAlias(alias string) string {
+	var sb strings.Builder
+	dash := false
+
+	for _, ch := range strings.ToLower(strings.TrimSpace(alias)) {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(ch)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return sb.String()
+}
+
+func validatedCopies(tables []CodonTable) ([]CodonTable, error) {
+	if len(tables) == 0 {
+		return nil, errors.New("no codon tables provided")
+	}
+
+	copied := make([]CodonTable, 0, len(tables))
+	seen := make(map[int]bool, len(tables))
+
+	for i := range tables {
+		if tables[i].ID <= 0 {
+			return nil, fmt.Errorf("invalid codon table id %d: expected positive number", tables[i].ID)
+		}
+		if seen[tables[i].ID] {
+			return nil, fmt.Errorf("duplicate codon table id %d", tables[i].ID)
+		}
+		if len(tables[i].Codons) == 0 {
+			return nil, fmt.Errorf("codon table no. %d has no codons", tables[i].ID)
+		}
+
+		err := validateCodonUsage(tables[i].Codons, true)
+		if err != nil {
+			return nil, fmt.Errorf("codon table no. %d: %w", tables[i].ID, err)
+		}
+
+		seen[tables[i].ID] = true
+		copied = append(copied, tables[i].Copy())
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,203 @@
+package sequence
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCodonTableRegistry_Lookup(t *testing.T) {
+	registry := NewCodonTableRegistry()
+
+	tests := []struct {
+		name       string
+		query      string
+		expectedID int
+		wantErr    bool
+	}{
+		{name: "numeric-id", query: "5", expectedID: 5},
+		{name: "short-alias", query: "mito-vert", expectedID: 2},
+		{name: "alias-case-insensitive", query: "Mito-Vert", expectedID: 2},
+		{name: "full-name", query: "Vertebrate Mitochondrial", expectedID: 2},
+		{name: "normalized-name", query: "invertebrate-mitochondrial", expectedID: 5},
+		{name: "gc-prt-short-name", query: "SGC4", expectedID: 5},
+		{name: "name-with-punctuation", query: "bacterial-archaeal-and-plant-plastid", expectedID: 11},
+		{name: "unknown-alias", query: "mito-unicorn", wantErr: true},
+		{name: "unknown-id", query: "99", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := registry.Lookup(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && table.ID != tt.expectedID {
+				t.Errorf("Lookup(%q) got table no. %d, expected %d", tt.query, table.ID, tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestCodonTableRegistry_RegisterDerived(t *testing.T) {
+	registry := NewCodonTableRegistry()
+	builtins := len(registry.Tables())
+
+	derived, err := registry.RegisterDerived(DerivedCodonTable{
+		BaseID:      1,
+		Name:        "Amber Recoded",
+		Aliases:     []string{"amber-free", "rec-1"},
+		Codons:      map[string]AminoAcid{"UAG": 'W', "AGA": '*'},
+		StartCodons: []string{"AUG"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterDerived() error = %v", err)
+	}
+
+	if derived.ID != firstCustomTableID {
+		t.Errorf("expected first custom table to get id %d, got %d", firstCustomTableID, derived.ID)
+	}
+
+	expectedStops := map[string]AminoAcid{"UAA": '*', "UGA": '*', "AGA": '*'}
+	if !reflect.DeepEqual(derived.StopCodons, expectedStops) {
+		t.Errorf("unexpected stop codons %v, expected %v", derived.StopCodons, expectedStops)
+	}
+	if !reflect.DeepEqual(derived.StartCodons, map[string]AminoAcid{"AUG": 'M'}) {
+		t.Errorf("unexpected start codons %v", derived.StartCodons)
+	}
+
+	for _, query := range []string{"amber-free", "REC-1", "Amber Recoded", fmt.Sprint(firstCustomTableID)} {
+		table, err := registry.Lookup(query)
+		if err != nil {
+			t.Errorf("Lookup(%q) error = %v", query, err)
+			continue
+		}
+		if table.Codons["UAG"] != 'W' {
+			t.Errorf("Lookup(%q) returned table without UAG reassignment", query)
+		}
+	}
+
+	// base table stays untouched
+	standard, _ := registry.Get(1)
+	if standard.Codons["UAG"] != '*' {
+		t.Errorf("base table was modified by derivation")
+	}
+
+	tables := registry.Tables()
+	if len(tables) != builtins+1 || tables[len(tables)-1].ID != derived.ID {
+		t.Errorf("expected derived table to be listed after the built-in ones")
+	}
+
+	second, err := registry.RegisterDerived(DerivedCodonTable{BaseID: derived.ID, Name: "Amber Recoded 2"})
+	if err != nil {
+		t.Fatalf("RegisterDerived() error = %v", err)
+	}
+	if second.ID != firstCustomTableID+1 {
+		t.Errorf("expected next custom id %d, got %d", firstCustomTableID+1, second.ID)
+	}
+
+	stopStart, err := registry.RegisterDerived(DerivedCodonTable{BaseID: 1, Name: "UUG Stop", Codons: map[string]AminoAcid{"UUG": '*'}})
+	if err != nil {
+		t.Fatalf("RegisterDerived() error = %v", err)
+	}
+	if _, isStart := stopStart.StartCodons["UUG"]; isStart {
+		t.Errorf("codon reassigned to a stop is still a start codon")
+	}
+	if _, isStart := stopStart.StartCodons["AUG"]; !isStart {
+		t.Errorf("expected the other start codons of the base table, got %v", stopStart.StartCodons)
+	}
+
+	aliases := registry.Aliases(derived.ID)
+	if !reflect.DeepEqual(aliases, []string{"amber-free", "amber-recoded", "rec-1"}) {
+		t.Errorf("unexpected aliases %v", aliases)
+	}
+}
+
+func TestCodonTableRegistry_RegisterDerived_Invalid(t *testing.T) {
+	registry := NewCodonTableRegistry()
+
+	tests := []struct {
+		name    string
+		derived DerivedCodonTable
+	}{
+		{name: "unknown-base", derived: DerivedCodonTable{BaseID: 99, Name: "custom"}},
+		{name: "no-name", derived: DerivedCodonTable{BaseID: 1}},
+		{name: "taken-alias", derived: DerivedCodonTable{BaseID: 1, Name: "custom", Aliases: []string{"mito-vert"}}},
+		{name: "numeric-alias", derived: DerivedCodonTable{BaseID: 1, Name: "custom", Aliases: []string{"42"}}},
+		{name: "invalid-codon", derived: DerivedCodonTable{BaseID: 1, Name: "custom", Codons: map[string]AminoAcid{"UXG": 'W'}}},
+		{name: "unknown-start", derived: DerivedCodonTable{BaseID: 1, Name: "custom", StartCodons: []string{"XXX"}}},
+		{name: "stop-start", derived: DerivedCodonTable{BaseID: 1, Name: "custom", StartCodons: []string{"UAA"}}},
+		{name: "builtin-id", derived: DerivedCodonTable{BaseID: 1, ID: 2, Name: "custom"}},
+		{name: "base-id", derived: DerivedCodonTable{BaseID: 1, ID: 1, Name: "custom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := registry.RegisterDerived(tt.derived); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+
+	if got, expected := len(registry.Tables()), len(codonTables); got != expected {
+		t.Errorf("failed registrations must not add tables: got %d, expected %d", got, expected)
+	}
+}
+
+func TestCodonTableRegistry_Concurrent(t *testing.T) {
+	registry := NewCodonTableRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := registry.RegisterDerived(DerivedCodonTable{BaseID: 2, Name: fmt.Sprintf("custom %d", i)})
+			if err != nil {
+				t.Errorf("RegisterDerived() error = %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := registry.Lookup("mito-vert"); err != nil {
+				t.Errorf("Lookup() error = %v", err)
+			}
+			_ = registry.Tables()
+		}()
+	}
+	wg.Wait()
+
+	if got, expected := len(registry.Tables()), len(codonTables)+20; got != expected {
+		t.Errorf("expected %d tables, got %d", expected, got)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	defer ResetCodonTables()
+
+	_, err := RegisterDerivedCodonTable(DerivedCodonTable{BaseID: 2, Name: "Mito Test", Aliases: []string{"mt"}})
+	if err != nil {
+		t.Fatalf("RegisterDerivedCodonTable() error = %v", err)
+	}
+
+	err = AddCodonTableAlias("vmt", 2)
+	if err != nil {
+		t.Fatalf("AddCodonTableAlias() error = %v", err)
+	}
+
+	if _, err = LookupCodonTable("mt"); err != nil {
+		t.Errorf("LookupCodonTable() error = %v", err)
+	}
+	if table, _ := LookupCodonTable("vmt"); table.ID != 2 {
+		t.Errorf("expected alias vmt to point to table 2, got %d", table.ID)
+	}
+
+	ResetCodonTables()
+	if _, err = LookupCodonTable("mt"); err == nil {
+		t.Errorf("expected custom alias to be dropped by reset")
+	}
+	if _, err = LookupCodonTable("mito-vert"); err != nil {
+		t.Errorf("expected built-in alias after reset: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package sequence
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -10,14 +11,43 @@ type RNASequence string
 
 var ErrTooShortSequence = errors.New("sequence length must be at least 3")
 var ErrRNAContainsT = errors.New("string contains T base and is not valid RNA sequence")
+var ErrPartialCodon = errors.New("sequence length is not a multiple of 3")
+var ErrNoStartCodon = errors.New("first codon is not a start codon")
+var ErrNoStopCodon = errors.New("last codon is not a stop codon")
+var ErrInternalStop = errors.New("internal stop codon")
 
+// TranslationOptions select NCBI-like translation modes. The zero value translates every codon
+// and fails on a trailing partial codon.
+type TranslationOptions struct {
+	// ToStop ends translation at the first stop codon, the stop itself is not included.
+	ToStop bool
+	// CDS requires a complete coding sequence: a start codon, a single final stop codon and a length
+	// that is a multiple of 3. The start codon is translated as 'M' and the final stop is not included.
+	CDS bool
+	// TrimPartialCodon ignores trailing bases of an incomplete codon instead of returning ErrPartialCodon.
+	TrimPartialCodon bool
+	// ErrorOnInternalStop fails when a stop codon is found before the last codon.
+	ErrorOnInternalStop bool
+}
+
+// NewRNASequence accepts IUPAC nucleotides and gaps in any case and returns the uppercase sequence.
 func NewRNASequence(input string) (RNASequence, error) {
-	upper := strings.ToUpper(input)
-	if strings.Contains(upper, "T") {
-		return "", ErrRNAContainsT
+	return NewRNASequenceWithOptions(input, SequenceOptions{})
+}
+
+// NewRNASequenceWithAlphabet returns an *InvalidCharacterError for characters missing from the alphabet,
+// it wraps ErrRNAContainsT if the character is T.
+func NewRNASequenceWithAlphabet(input string, alphabet *Alphabet) (RNASequence, error) {
+	return NewRNASequenceWithOptions(input, SequenceOptions{Alphabet: alphabet})
+}
+
+func NewRNASequenceWithOptions(input string, options SequenceOptions) (RNASequence, error) {
+	err := validateSequence(input, options.alphabet(RNAGappedAlphabet), 'T', ErrRNAContainsT)
+	if err != nil {
+		return "", err
 	}
 
-	return RNASequence(upper), nil
+	return RNASequence(options.applyCase(input)), nil
 }
 
 func (r RNASequence) String() string {
@@ -28,69 +58,351 @@ func (r RNASequence) Length() int {
 	return len(r)
 }
 
+// cDNAMapForRNA pairs RNA bases with the complementary DNA bases of the same case
+var cDNAMapForRNA = func() map[Nucleotide]Nucleotide {
+	cDNAMap := make(map[Nucleotide]Nucleotide, len(complementMapForDNA))
+	for base, complement := range complementMapForDNA {
+		if base != 'T' && base != 't' {
+			cDNAMap[base] = complement
+		}
+	}
+	cDNAMap['U'] = 'A'
+	cDNAMap['u'] = 'a'
+
+	return cDNAMap
+}()
+
+// ReverseTranscribe returns the 5'->3' complementary DNA (first-strand cDNA) of r.
+func (r RNASequence) ReverseTranscribe() DNASequence {
+	return r.ReverseTranscribeWithOptions(SequenceOptions{})
+}
+
+// ReverseTranscribeWithOptions keeps soft-masked (lowercase) bases with PreserveCase, the alphabet is ignored.
+func (r RNASequence) ReverseTranscribeWithOptions(options SequenceOptions) DNASequence {
+	seqLength := len(r)
+	cDNA := make([]Nucleotide, seqLength)
+
+	for i, base := range []Nucleotide(r) {
+		dnaBase, ok := cDNAMapForRNA[base]
+		if !ok {
+			dnaBase = '-'
+		}
+		cDNA[seqLength-i-1] = dnaBase
+	}
+
+	return DNASequence(options.applyCase(string(cDNA)))
+}
+
+// BackTranscribe returns the coding strand DNA of r, i.e. r with U replaced by T.
+func (r RNASequence) BackTranscribe() DNASequence {
+	return r.BackTranscribeWithOptions(SequenceOptions{})
+}
+
+func (r RNASequence) BackTranscribeWithOptions(options SequenceOptions) DNASequence {
+	return DNASequence(options.applyCase(strings.Map(func(b rune) rune {
+		switch b {
+		case 'U':
+			return 'T'
+		case 'u':
+			return 't'
+		}
+		return b
+	}, string(r))))
+}
+
 func (r RNASequence) Translate(codonTable *CodonTable) (ProteinSequence, error) {
+	// Ignore any partial codon at the end of the sequence
+	return r.TranslateWithOptions(codonTable, TranslationOptions{TrimPartialCodon: true})
+}
+
+func (r RNASequence) TranslateWithOptions(codonTable *CodonTable, options TranslationOptions) (ProteinSequence, error) {
+	protein := make([]AminoAcid, 0, len(r)/3)
+
+	err := translate(r, codonTable, options, func(aa AminoAcid) {
+		protein = append(protein, aa)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return ProteinSequence(protein), nil
+}
+
+// codonTranslator is implemented by CodonTable and CompiledCodonTable
+type codonTranslator interface {
+	translateCodon(codon string) AminoAcid
+	isStartCodon(codon string) bool
+	isStopCodon(codon string) bool
+}
+
+func (c *CodonTable) translateCodon(codon string) AminoAcid {
+	return translateCodonFast(c, codon)
+}
+
+func (c *CodonTable) isStartCodon(codon string) bool {
+	_, isStart := c.StartCodons[strings.ToUpper(codon)]
+	return isStart
+}
+
+func (c *CodonTable) isStopCodon(codon string) bool {
+	_, isStop := c.StopCodons[strings.ToUpper(codon)]
+	return isStop || c.TranslateCodon(codon) == '*'
+}
+
+// translate passes the amino acids of r to emit, it doesn't allocate unless it fails.
+func translate(r RNASequence, translator codonTranslator, options TranslationOptions, emit func(AminoAcid)) error {
 	seqLength := len(r)
 
 	if seqLength < 3 {
-		return "", ErrTooShortSequence
+		return ErrTooShortSequence
+	}
+
+	if seqLength%3 != 0 && (options.CDS || !options.TrimPartialCodon) {
+		return fmt.Errorf("%w: %d trailing bases", ErrPartialCodon, seqLength%3)
 	}
 
-	// Ignore any partial codon at the end of the sequence
 	codonsToTranslate := seqLength / 3
-	protein := make([]AminoAcid, codonsToTranslate)
+
+	if options.CDS {
+		first := string(r[:3])
+		if !translator.isStartCodon(first) {
+			return fmt.Errorf("%w: %s", ErrNoStartCodon, strings.ToUpper(first))
+		}
+
+		last := string(r[seqLength-3:])
+		if !translator.isStopCodon(last) {
+			return fmt.Errorf("%w: %s", ErrNoStopCodon, strings.ToUpper(last))
+		}
+
+		// the final stop codon is not translated
+		codonsToTranslate--
+	}
 
 	for i := 0; i < codonsToTranslate*3; i += 3 {
-		codon := strings.ToUpper(string(r[i : i+3]))
-		protein[i/3] = codonTable.TranslateCodon(codon)
+		codon := string(r[i : i+3])
+		aa := translator.translateCodon(codon)
+
+		if options.CDS && i == 0 {
+			// alternative start codons still encode methionine at the initiator position
+			aa = 'M'
+		}
+
+		if aa == '*' {
+			if options.ToStop && !options.CDS {
+				break
+			}
+
+			isLastCodon := i == (seqLength/3-1)*3
+			if (options.CDS || options.ErrorOnInternalStop) && !isLastCodon {
+				return fmt.Errorf("%w %s at position %d", ErrInternalStop, strings.ToUpper(codon), i)
+			}
+		}
+
+		emit(aa)
 	}
 
-	return ProteinSequence(protein), nil
+	return nil
 }
 
+type Strand int
+
+const (
+	ForwardStrand Strand = 1
+	ReverseStrand Strand = -1
+)
+
+// ORF is an open reading frame. Start and End are 0-based, end-exclusive positions on the forward strand,
+// Frame (1-3) is counted from the 5' end of the strand the ORF is on.
 type ORF struct {
-	Start      int
-	End        int
-	Codons     int
-	Frame      int
-	ProteinSeq ProteinSequence
+	Start  int
+	End    int
+	Codons int
+	Frame  int
+	Strand Strand
+	// PartialStart is set when the ORF runs off the 5' end of its strand without a start codon,
+	// PartialEnd when it runs off the 3' end without a stop codon.
+	PartialStart bool
+	PartialEnd   bool
+	ProteinSeq   ProteinSequence
 }
 
-func (r RNASequence) FindORFs(minCodons int, codonTable *CodonTable) ([]ORF, error) {
-	var orfs []ORF
-	if minCodons < 1 {
-		minCodons = 1
+type StartCodonPolicy int
+
+const (
+	// TableStartCodons accepts every start codon of the codon table, including alternative ones.
+	TableStartCodons StartCodonPolicy = iota
+	// ATGOnly accepts only the canonical AUG start codon.
+	ATGOnly
+)
+
+// ORFOptions configure the ORF search. The zero value reports every ORF, including nested ones,
+// using the standard genetic code.
+type ORFOptions struct {
+	// CodonTable defaults to the standard genetic code (table no. 1).
+	CodonTable *CodonTable
+	MinCodons  int
+	// MaxCodons is the length limit of reported ORFs including the stop codon, 0 means no limit.
+	MaxCodons int
+	// LongestOnly reports a single ORF per stop codon, starting at the most upstream start codon,
+	// instead of an ORF for every in-frame start codon.
+	LongestOnly bool
+	StartCodons StartCodonPolicy
+	// Partial also reports ORFs open at either end of the sequence. Ignored for circular sequences.
+	Partial bool
+	// IgnoreAmbiguous skips ORFs containing codons with ambiguous bases.
+	IgnoreAmbiguous bool
+	// Circular treats the sequence as a circular molecule (a plasmid or an organelle genome),
+	// so ORFs may span the origin. Such ORFs have End <= Start, End being counted from the origin.
+	Circular bool
+}
+
+func (o ORFOptions) codonTable() (*CodonTable, error) {
+	if o.CodonTable != nil {
+		return o.CodonTable, nil
+	}
+
+	table, err := GetCodonTable(1)
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (o ORFOptions) isStartCodon(table *CodonTable, codon string) bool {
+	if o.StartCodons == ATGOnly {
+		return codon == "AUG"
+	}
+
+	_, isStart := table.StartCodons[codon]
+	return isStart
+}
+
+func (r RNASequence) FindORFs(options ORFOptions) ([]ORF, error) {
+	codonTable, err := options.codonTable()
+	if err != nil {
+		return nil, err
 	}
 
-	seq := string(r)
+	var orfs []ORF
 	for frame := 0; frame < 3; frame++ {
-		for i := frame; i <= len(seq)-3; i += 3 {
-			_, isStartCodon := codonTable.StartCodons[seq[i:i+3]]
-			if isStartCodon {
-				for j := i + 3; j <= len(seq)-3; j += 3 {
-					codon := seq[j : j+3]
-					_, isStopCodon := codonTable.StopCodons[codon]
-					if isStopCodon {
-						length := (j + 3 - i) / 3
-						if length >= minCodons {
-							prot, err := r[i : j+3].Translate(codonTable)
-							if err != nil {
-								return nil, err
-							}
-
-							orf := ORF{
-								Start:      i,
-								End:        j + 3,
-								Codons:     length,
-								Frame:      frame + 1,
-								ProteinSeq: prot,
-							}
-							orfs = append(orfs, orf)
-						}
-						break
-					}
-				}
+		orfs = append(orfs, r.findFrameORFs(frame, codonTable, options)...)
+	}
+
+	return orfs, nil
+}
+
+// findFrameORFs walks the codons of a single frame once, keeping the start codons seen since the last stop,
+// so each stop codon closes all of its ORFs at once. The frame is translated lazily: only codons of reported
+// ORFs are translated, each of them once, and nested ORFs share the translation.
+func (r RNASequence) findFrameORFs(frame int, codonTable *CodonTable, options ORFOptions) []ORF {
+	var orfs []ORF
+
+	seqLength := len(r)
+	// codon lookups need uppercase, soft-masked bases are found too
+	seq := strings.ToUpper(string(r))
+	partial := options.Partial && !options.Circular
+	if options.Circular {
+		// walk the sequence twice so ORFs starting before the origin can be followed across it
+		seq += seq
+	}
+
+	var starts []int
+	// only the region before the first stop codon may be open at the 5' end
+	openStart := partial
+	lastAmbiguous := -1
+	// translated codons of the frame, zero until needed
+	var translation []AminoAcid
+
+	// emit reports the ORF from start to end and whether it passed the filters
+	emit := func(start, end int, partialStart, partialEnd bool) bool {
+		length := (end - start) / 3
+		if length < options.MinCodons || (options.MaxCodons > 0 && length > options.MaxCodons) {
+			return false
+		}
+		if options.IgnoreAmbiguous && lastAmbiguous >= start {
+			return false
+		}
+
+		if translation == nil {
+			translation = make([]AminoAcid, (len(seq)-frame)/3)
+		}
+		first, last := (start-frame)/3, (end-frame)/3
+		for k := first; k < last; k++ {
+			if translation[k] == 0 {
+				i := frame + k*3
+				translation[k] = translateCodonFast(codonTable, seq[i:i+3])
 			}
 		}
+		prot := ProteinSequence(translation[first:last])
+
+		if end > seqLength {
+			end -= seqLength
+		}
+
+		orfs = append(orfs, ORF{
+			Start:        start,
+			End:          end,
+			Codons:       length,
+			Frame:        frame + 1,
+			Strand:       ForwardStrand,
+			PartialStart: partialStart,
+			PartialEnd:   partialEnd,
+			ProteinSeq:   prot,
+		})
+		return true
 	}
-	return orfs, nil
+
+	// emitAll reports ORFs from every pending start up to end. With LongestOnly it stops at the first
+	// reported one, so nested ORFs are still found when the longer ones are filtered out.
+	emitAll := func(end int, partialEnd bool) {
+		if openStart && (len(starts) == 0 || starts[0] != frame) {
+			if emit(frame, end, true, partialEnd) && options.LongestOnly {
+				return
+			}
+		}
+
+		for _, start := range starts {
+			// an ORF can't be longer than the whole circular molecule
+			if end-start > seqLength {
+				continue
+			}
+			if emit(start, end, false, partialEnd) && options.LongestOnly {
+				return
+			}
+		}
+	}
+
+	for i := frame; i+3 <= len(seq); i += 3 {
+		codon := seq[i : i+3]
+		if strings.Trim(codon, "ACGU") != "" {
+			lastAmbiguous = i
+		}
+
+		if _, isStopCodon := codonTable.StopCodons[codon]; isStopCodon {
+			emitAll(i+3, false)
+			starts = starts[:0]
+			openStart = false
+			continue
+		}
+
+		// starts past the origin were already seen in the first walk around a circular sequence
+		if i < seqLength && options.isStartCodon(codonTable, codon) {
+			starts = append(starts, i)
+		}
+	}
+
+	if partial && seqLength-frame >= 3 {
+		emitAll(seqLength-(seqLength-frame)%3, true)
+	}
+
+	return orfs
+}
+
+// translateCodonFast looks up unambiguous uppercase codons directly and falls back to TranslateCodon.
+func translateCodonFast(codonTable *CodonTable, codon string) AminoAcid {
+	if aa, ok := codonTable.Codons[codon]; ok {
+		return aa
+	}
+
+	return codonTable.TranslateCodon(codon)
 }
@@ -1,6 +1,8 @@
 package sequence
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -50,6 +52,186 @@ func TestRNASequence_Translate(t *testing.T) {
 	}
 }
 
+func TestRNASequence_ReverseTranscribe(t *testing.T) {
+	tests := []struct {
+		name         string
+		rna          RNASequence
+		options      SequenceOptions
+		expectedCDNA DNASequence
+		expectedDNA  DNASequence
+	}{
+		{
+			name:         "simple_sequence",
+			rna:          "AUGCCCUAA",
+			expectedCDNA: "TTAGGGCAT",
+			expectedDNA:  "ATGCCCTAA",
+		},
+		{
+			name:         "mixed_case_ambiguous",
+			rna:          "augRYn",
+			expectedCDNA: "NRYCAT",
+			expectedDNA:  "ATGRYN",
+		},
+		{
+			name:         "soft_masked_preserve_case",
+			rna:          "augRYn",
+			options:      SequenceOptions{PreserveCase: true},
+			expectedCDNA: "nRYcat",
+			expectedDNA:  "atgRYn",
+		},
+		{
+			name:         "invalid_base",
+			rna:          "AUT",
+			expectedCDNA: "-AT",
+			expectedDNA:  "ATT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cDNA, dna := tt.rna.ReverseTranscribe(), tt.rna.BackTranscribe()
+			if tt.options.PreserveCase {
+				cDNA, dna = tt.rna.ReverseTranscribeWithOptions(tt.options), tt.rna.BackTranscribeWithOptions(tt.options)
+			}
+			if cDNA != tt.expectedCDNA {
+				t.Errorf("ReverseTranscribe() = %v, expected %v", cDNA, tt.expectedCDNA)
+			}
+			if dna != tt.expectedDNA {
+				t.Errorf("BackTranscribe() = %v, expected %v", dna, tt.expectedDNA)
+			}
+		})
+	}
+
+	// reverse transcription of the template transcript gives back the template
+	dna := DNASequence("ATGCGAATTCAG")
+	if got := dna.TranscribeTemplate().ReverseTranscribe(); got != dna {
+		t.Errorf("ReverseTranscribe(TranscribeTemplate()) = %v, expected %v", got, dna)
+	}
+}
+
+func TestRNASequence_TranslateWithOptions(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+	bacterialTable, _ := GetCodonTable(11)
+
+	tests := []struct {
+		name         string
+		rna          RNASequence
+		table        *CodonTable
+		options      TranslationOptions
+		expectedProt ProteinSequence
+		expectedErr  error
+	}{
+		{
+			name:         "DefaultTranslatesThroughStops",
+			rna:          "AUGUUUUAGGGC",
+			table:        &standardTable,
+			expectedProt: "MF*G",
+		},
+		{
+			name:        "DefaultRejectsPartialCodon",
+			rna:         "AUGUUUAG",
+			table:       &standardTable,
+			expectedErr: ErrPartialCodon,
+		},
+		{
+			name:         "TrimPartialCodon",
+			rna:          "AUGUUUAG",
+			table:        &standardTable,
+			options:      TranslationOptions{TrimPartialCodon: true},
+			expectedProt: "MF",
+		},
+		{
+			name:         "ToStop",
+			rna:          "AUGUUUUAGGGCUAA",
+			table:        &standardTable,
+			options:      TranslationOptions{ToStop: true},
+			expectedProt: "MF",
+		},
+		{
+			name:         "ToStopWithoutStop",
+			rna:          "AUGUUUGGC",
+			table:        &standardTable,
+			options:      TranslationOptions{ToStop: true},
+			expectedProt: "MFG",
+		},
+		{
+			name:        "InternalStop",
+			rna:         "AUGUUUUAGGGC",
+			table:       &standardTable,
+			options:     TranslationOptions{ErrorOnInternalStop: true},
+			expectedErr: ErrInternalStop,
+		},
+		{
+			name:         "TerminalStopIsNotInternal",
+			rna:          "AUGUUUGGCUAA",
+			table:        &standardTable,
+			options:      TranslationOptions{ErrorOnInternalStop: true},
+			expectedProt: "MFG*",
+		},
+		{
+			name:         "CDSAlternativeStartAsMethionine",
+			rna:          "UUGUUUGGCUAA",
+			table:        &bacterialTable,
+			options:      TranslationOptions{CDS: true},
+			expectedProt: "MFG",
+		},
+		{
+			name:         "CDSLowerCase",
+			rna:          "auguuuggcuga",
+			table:        &standardTable,
+			options:      TranslationOptions{CDS: true},
+			expectedProt: "MFG",
+		},
+		{
+			name:        "CDSWithoutStart",
+			rna:         "UUUUUUGGCUAA",
+			table:       &standardTable,
+			options:     TranslationOptions{CDS: true},
+			expectedErr: ErrNoStartCodon,
+		},
+		{
+			name:        "CDSWithoutStop",
+			rna:         "AUGUUUGGC",
+			table:       &standardTable,
+			options:     TranslationOptions{CDS: true},
+			expectedErr: ErrNoStopCodon,
+		},
+		{
+			name:        "CDSWithInternalStop",
+			rna:         "AUGUAAGGCUAA",
+			table:       &standardTable,
+			options:     TranslationOptions{CDS: true},
+			expectedErr: ErrInternalStop,
+		},
+		{
+			name:        "CDSWithPartialCodon",
+			rna:         "AUGUUUGGCUAAG",
+			table:       &standardTable,
+			options:     TranslationOptions{CDS: true, TrimPartialCodon: true},
+			expectedErr: ErrPartialCodon,
+		},
+		{
+			name:        "TooShort",
+			rna:         "AU",
+			table:       &standardTable,
+			options:     TranslationOptions{CDS: true},
+			expectedErr: ErrTooShortSequence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prot, err := tt.rna.TranslateWithOptions(tt.table, tt.options)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if prot != tt.expectedProt {
+				t.Errorf("Expected protein sequence: %s, got: %s", tt.expectedProt, prot)
+			}
+		})
+	}
+}
+
 func TestRNASequence_FindORFs(t *testing.T) {
 	standartTable, _ := GetCodonTable(1)
 
@@ -171,7 +353,7 @@ func TestRNASequence_FindORFs(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			orfs, err := tc.r.FindORFs(tc.minCodons, &standartTable)
+			orfs, err := tc.r.FindORFs(ORFOptions{CodonTable: &standartTable, MinCodons: tc.minCodons})
 			if err != nil {
 				t.Fatalf("FindORFs failed: %v", err)
 			}
@@ -192,3 +374,220 @@ func TestRNASequence_FindORFs(t *testing.T) {
 		})
 	}
 }
+
+func TestRNASequence_FindORFs_Circular(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+
+	testCases := []struct {
+		name     string
+		r        RNASequence
+		expected []ORF
+	}{
+		{
+			name: "ORFAcrossOrigin",
+			// AUG CCC at the end continues with GGG UAA from the beginning
+			r: "GGGUAAAAAAUGCCC",
+			expected: []ORF{
+				{Start: 9, End: 6, Codons: 4, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MPG*"},
+			},
+		},
+		{
+			name: "ORFWithoutWrap",
+			r:    "AUGCCCUAAGG",
+			expected: []ORF{
+				{Start: 0, End: 9, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name: "StartCodonAcrossOrigin",
+			// UG + A form AUG across the origin
+			r: "UGCCCUAAGA",
+			expected: []ORF{
+				{Start: 9, End: 8, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name:     "NoStopAroundCircle",
+			r:        "AUGCCCGGG",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orfs, err := tc.r.FindORFs(ORFOptions{CodonTable: &standardTable, MinCodons: 1, Circular: true})
+			if err != nil {
+				t.Fatalf("FindORFs failed: %v", err)
+			}
+			if !reflect.DeepEqual(orfs, tc.expected) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, orfs)
+			}
+		})
+	}
+
+	linear, _ := RNASequence("GGGUAAAAAAUGCCC").FindORFs(ORFOptions{CodonTable: &standardTable, MinCodons: 1})
+	if len(linear) != 0 {
+		t.Errorf("Expected no ORFs in linear sequence, got %+v", linear)
+	}
+}
+
+func TestRNASequence_FindORFs_Options(t *testing.T) {
+	standardTable, _ := GetCodonTable(1)
+
+	testCases := []struct {
+		name     string
+		r        RNASequence
+		options  ORFOptions
+		expected []ORF
+	}{
+		{
+			name:    "NestedORFs",
+			r:       "AUGAUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable},
+			expected: []ORF{
+				{Start: 0, End: 12, Codons: 4, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MMP*"},
+				{Start: 3, End: 12, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name:    "LongestOnly",
+			r:       "AUGAUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable, LongestOnly: true},
+			expected: []ORF{
+				{Start: 0, End: 12, Codons: 4, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MMP*"},
+			},
+		},
+		{
+			name:    "MaxCodons",
+			r:       "AUGAUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable, MaxCodons: 3},
+			expected: []ORF{
+				{Start: 3, End: 12, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name:    "AlternativeStart",
+			r:       "UUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable},
+			expected: []ORF{
+				{Start: 0, End: 9, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "LP*"},
+			},
+		},
+		{
+			name:     "ATGOnly",
+			r:        "UUGCCCUAA",
+			options:  ORFOptions{CodonTable: &standardTable, StartCodons: ATGOnly},
+			expected: nil,
+		},
+		{
+			name:    "PartialStart",
+			r:       "GCCUAA",
+			options: ORFOptions{CodonTable: &standardTable, MinCodons: 2, Partial: true},
+			expected: []ORF{
+				{Start: 0, End: 6, Codons: 2, Frame: 1, Strand: ForwardStrand, PartialStart: true, ProteinSeq: "A*"},
+			},
+		},
+		{
+			name:    "PartialBothEnds",
+			r:       "CCCAUGCCC",
+			options: ORFOptions{CodonTable: &standardTable, MinCodons: 2, Partial: true},
+			expected: []ORF{
+				{Start: 0, End: 9, Codons: 3, Frame: 1, Strand: ForwardStrand, PartialStart: true, PartialEnd: true, ProteinSeq: "PMP"},
+				{Start: 3, End: 9, Codons: 2, Frame: 1, Strand: ForwardStrand, PartialEnd: true, ProteinSeq: "MP"},
+				{Start: 1, End: 7, Codons: 2, Frame: 2, Strand: ForwardStrand, PartialStart: true, PartialEnd: true, ProteinSeq: "PC"},
+				{Start: 2, End: 8, Codons: 2, Frame: 3, Strand: ForwardStrand, PartialStart: true, PartialEnd: true, ProteinSeq: "HA"},
+			},
+		},
+		{
+			name:    "PartialLongestOnly",
+			r:       "CCCAUGCCC",
+			options: ORFOptions{CodonTable: &standardTable, MinCodons: 3, Partial: true, LongestOnly: true},
+			expected: []ORF{
+				{Start: 0, End: 9, Codons: 3, Frame: 1, Strand: ForwardStrand, PartialStart: true, PartialEnd: true, ProteinSeq: "PMP"},
+			},
+		},
+		{
+			name:    "IgnoreAmbiguous",
+			r:       "AUGNCCUAAAUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable, IgnoreAmbiguous: true},
+			expected: []ORF{
+				{Start: 9, End: 18, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name:    "IgnoreAmbiguousLongestOnly",
+			r:       "AUGNCCAUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable, IgnoreAmbiguous: true, LongestOnly: true},
+			expected: []ORF{
+				{Start: 6, End: 15, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name:    "MaxCodonsLongestOnly",
+			r:       "AUGAUGCCCUAA",
+			options: ORFOptions{CodonTable: &standardTable, MaxCodons: 3, LongestOnly: true},
+			expected: []ORF{
+				{Start: 3, End: 12, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+		{
+			name:    "DefaultCodonTable",
+			r:       "AUGCCCUAA",
+			options: ORFOptions{},
+			expected: []ORF{
+				{Start: 0, End: 9, Codons: 3, Frame: 1, Strand: ForwardStrand, ProteinSeq: "MP*"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orfs, err := tc.r.FindORFs(tc.options)
+			if err != nil {
+				t.Fatalf("FindORFs failed: %v", err)
+			}
+			if !reflect.DeepEqual(orfs, tc.expected) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, orfs)
+			}
+		})
+	}
+}
+
+func BenchmarkRNASequence_FindORFs(b *testing.B) {
+	rna := syntheticGenome(2_000_000).TranscribeCoding()
+	bacterialTable, _ := GetCodonTable(11)
+	options := ORFOptions{CodonTable: &bacterialTable, MinCodons: 100}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := rna.FindORFs(options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRNASequence_Translate(b *testing.B) {
+	genes := syntheticGenes(1000)
+	standardTable, _ := GetCodonTable(1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, gene := range genes {
+			if _, err := gene.Translate(&standardTable); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// syntheticGenes splits the synthetic genome into a set of n transcripts of about 1 kb
+func syntheticGenes(n int) []RNASequence {
+	rna := syntheticGenome(n * 1000).TranscribeCoding()
+
+	genes := make([]RNASequence, n)
+	for i := range genes {
+		genes[i] = rna[i*1000 : (i+1)*1000]
+	}
+	return genes
+}
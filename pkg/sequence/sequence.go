@@ -2,11 +2,36 @@ package sequence
 
 import "strings"
 
+// SequenceOptions configure the validating sequence constructors and the WithOptions variants of complement
+// and transcription, which only use PreserveCase.
+type SequenceOptions struct {
+	// Alphabet defaults to the gapped IUPAC alphabet of the sequence type.
+	Alphabet *Alphabet
+	// PreserveCase keeps lowercase (soft-masked) bases instead of converting them to uppercase.
+	PreserveCase bool
+}
+
+func (o SequenceOptions) alphabet(defaultAlphabet *Alphabet) *Alphabet {
+	if o.Alphabet == nil {
+		return defaultAlphabet
+	}
+	return o.Alphabet
+}
+
+func (o SequenceOptions) applyCase(input string) string {
+	if o.PreserveCase {
+		return input
+	}
+	return strings.ToUpper(input)
+}
+
 type NucleotideSequence interface {
 	String() string
 	Length() int
 }
 
+// GCContent returns the fraction of literal G and C letters among all letters. For sequences with N runs,
+// gaps or other ambiguity codes use Composition instead.
 func GCContent[S NucleotideSequence](seq S) float64 {
 	if seq.Length() == 0 {
 		return 0.0
@@ -0,0 +1,90 @@
+package sets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+	"github.com/dissipative/ribosome/pkg/sequence"
+)
+
+// CodonUsage counts the codons of the CDS features of every record. Spans on the complement strand are
+// reverse complemented and /codon_start is honored. Genes are identified by their locus_tag, gene or
+// protein_id qualifier, or by the record ID and the location when none is present.
+func (s *Set) CodonUsage(codonTable *sequence.CodonTable) (*sequence.CodonUsageTable, error) {
+	table := sequence.NewCodonUsageTable(codonTable)
+
+	for _, record := range s.records {
+		for _, feature := range record.Features {
+			if feature.Type != "CDS" {
+				continue
+			}
+
+			cds, err := s.extractFeature(record, feature)
+			if err != nil {
+				return nil, fmt.Errorf("record %s: %w", record.ID, err)
+			}
+
+			table.AddGene(featureID(record, feature), cds)
+		}
+	}
+
+	return table, nil
+}
+
+// extractFeature joins the spans of the feature into its coding RNA sequence
+func (s *Set) extractFeature(record bioio.Record, feature bioio.Feature) (sequence.RNASequence, error) {
+	spans, err := feature.Spans()
+	if err != nil {
+		return "", err
+	}
+
+	// RNA records are handled as their DNA copy, so complement spans can be read the same way
+	seq := record.Sequence
+	if s.molType == RNA {
+		seq = strings.NewReplacer("U", "T", "u", "t").Replace(seq)
+	}
+
+	var cds strings.Builder
+	for _, span := range spans {
+		if span.End > len(seq) {
+			return "", fmt.Errorf("location %s is out of the sequence bounds", feature.Location)
+		}
+
+		part := sequence.DNASequence(seq[span.Start:span.End])
+		if span.Complement {
+			part = part.ReverseComplement()
+		}
+		cds.WriteString(part.String())
+	}
+
+	dna, err := sequence.NewDNASequence(cds.String())
+	if err != nil {
+		return "", err
+	}
+	rna := dna.TranscribeCoding()
+
+	if codonStart, ok := feature.Qualifier("codon_start"); ok {
+		offset, err := strconv.Atoi(codonStart)
+		if err != nil || offset < 1 || offset > 3 {
+			return "", fmt.Errorf("invalid codon_start %q", codonStart)
+		}
+		if offset-1 >= len(rna) {
+			return "", fmt.Errorf("codon_start %d is past the end of the %d nt feature at %s", offset, len(rna), feature.Location)
+		}
+		rna = rna[offset-1:]
+	}
+
+	return rna, nil
+}
+
+func featureID(record bioio.Record, feature bioio.Feature) string {
+	for _, qualifier := range []string{"locus_tag", "gene", "protein_id"} {
+		if id, _ := feature.Qualifier(qualifier); id != "" {
+			return id
+		}
+	}
+
+	return record.ID + ":" + feature.Location
+}
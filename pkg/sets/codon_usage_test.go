@@ -0,0 +1,56 @@
+package sets
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dissipative/ribosome/pkg/bioio"
+	"github.com/dissipative/ribosome/pkg/sequence"
+)
+
+func TestSet_CodonUsage(t *testing.T) {
+	table, err := sequence.GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("Unexpected error while getting codon table: %v", err)
+	}
+
+	records := []bioio.Record{
+		{
+			ID:       "Seq1",
+			Sequence: "ATGAAATTTAAATAACCCTTATTTCCCAT",
+			Features: []bioio.Feature{
+				{Type: "source", Location: "1..29"},
+				{Type: "CDS", Location: "1..15", Qualifiers: map[string][]string{"locus_tag": {"S1_0001"}}},
+				// reverse strand ATGGGAAATAAGGG, read from the third base
+				{Type: "CDS", Location: "complement(16..29)", Qualifiers: map[string][]string{"codon_start": {"3"}}},
+			},
+		},
+	}
+
+	usage, err := NewDNASet(records).CodonUsage(&table)
+	if err != nil {
+		t.Fatalf("CodonUsage() error = %v", err)
+	}
+
+	expectedGenes := []sequence.GeneCodonCounts{
+		{ID: "S1_0001", Counts: sequence.CodonCounts{"AUG": 1, "AAA": 2, "UUU": 1, "UAA": 1}},
+		{ID: "Seq1:complement(16..29)", Counts: sequence.CodonCounts{"GGG": 2, "AAA": 1, "UAA": 1}},
+	}
+
+	if !reflect.DeepEqual(usage.Genes, expectedGenes) {
+		t.Errorf("CodonUsage() genes = %+v, expected %+v", usage.Genes, expectedGenes)
+	}
+	if usage.Counts["AAA"] != 3 || usage.Counts.Total() != 9 {
+		t.Errorf("unexpected total counts %v", usage.Counts)
+	}
+
+	records[0].Features = []bioio.Feature{{Type: "CDS", Location: "1..100"}}
+	if _, err = NewDNASet(records).CodonUsage(&table); err == nil {
+		t.Errorf("expected error for location out of bounds")
+	}
+
+	records[0].Features = []bioio.Feature{{Type: "CDS", Location: "1..2", Qualifiers: map[string][]string{"codon_start": {"3"}}}}
+	if _, err = NewDNASet(records).CodonUsage(&table); err == nil {
+		t.Errorf("expected error for codon_start past the end of the feature")
+	}
+}
@@ -2,7 +2,6 @@ package sets
 
 import (
 	"context"
-	"errors"
 	"github.com/dissipative/ribosome/pkg/bioio"
 	"github.com/dissipative/ribosome/pkg/sequence"
 	"sync"
@@ -32,16 +31,24 @@ func NewRNASet(sequences []bioio.Record) *Set {
 	}
 }
 
+// Records returns the records of the set, they must not be modified.
+func (s *Set) Records() []bioio.Record {
+	return s.records
+}
+
+// MolType returns DNA or RNA.
+func (s *Set) MolType() int {
+	return s.molType
+}
+
 type ORFs struct {
 	sync.Mutex
 	mapped map[string][]sequence.ORF // record ID -> []ORF
 }
 
-func (s *Set) FindORFs(minCodons int, codonTable *sequence.CodonTable) (*ORFs, error) {
-	if s.molType == DNA {
-		return nil, errors.New("transcribe records to RNA first")
-	}
-
+// FindORFs searches every record, DNA records are searched in all six frames.
+// The topology of each record decides whether it is searched as a circular sequence, options.Circular is ignored.
+func (s *Set) FindORFs(options sequence.ORFOptions) (*ORFs, error) {
 	var orfs ORFs
 	orfs.mapped = make(map[string][]sequence.ORF)
 
@@ -59,19 +66,15 @@ func (s *Set) FindORFs(minCodons int, codonTable *sequence.CodonTable) (*ORFs, e
 			case <-ctx.Done():
 				return // if context is done, return immediately
 			default:
-				rna, err := sequence.NewRNASequence(record.Sequence)
+				found, err := s.findRecordORFs(record, options)
 				if err != nil {
 					cancel(err)
 					return
 				}
 
 				orfs.Lock()
-				orfs.mapped[record.ID], err = rna.FindORFs(minCodons, codonTable)
+				orfs.mapped[record.ID] = found
 				orfs.Unlock()
-
-				if err != nil {
-					cancel(err)
-				}
 			}
 		}(record)
 	}
@@ -84,3 +87,31 @@ func (s *Set) FindORFs(minCodons int, codonTable *sequence.CodonTable) (*ORFs, e
 
 	return &orfs, nil
 }
+
+func (s *Set) findRecordORFs(record bioio.Record, options sequence.ORFOptions) ([]sequence.ORF, error) {
+	options.Circular = record.Circular
+
+	if s.molType == DNA {
+		dna, err := sequence.NewDNASequence(record.Sequence)
+		if err != nil {
+			return nil, err
+		}
+
+		return dna.FindORFs(options)
+	}
+
+	rna, err := sequence.NewRNASequence(record.Sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	return rna.FindORFs(options)
+}
+
+// Get returns ORFs found in the record with the given ID.
+func (o *ORFs) Get(recordID string) []sequence.ORF {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.mapped[recordID]
+}
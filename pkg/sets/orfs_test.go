@@ -66,7 +66,7 @@ func TestFindORFs(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			set := NewRNASet(tc.sequences)
-			orfs, err := set.FindORFs(tc.minCodons, &table)
+			orfs, err := set.FindORFs(sequence.ORFOptions{CodonTable: &table, MinCodons: tc.minCodons})
 			if tc.expectedErr != nil && err == nil {
 				t.Errorf("Expected error, but got no error")
 			} else if tc.expectedErr == nil && err != nil {
@@ -79,3 +79,57 @@ func TestFindORFs(t *testing.T) {
 		})
 	}
 }
+
+func TestFindORFs_DNASixFrames(t *testing.T) {
+	table, err := sequence.GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("Unexpected error while getting codon table: %v", err)
+	}
+
+	set := NewDNASet([]bioio.Record{
+		{ID: "forward", Sequence: "ATGAAATAG"},
+		{ID: "reverse", Sequence: "CTATTTCAT"},
+	})
+
+	orfs, err := set.FindORFs(sequence.ORFOptions{CodonTable: &table, MinCodons: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for id, strand := range map[string]sequence.Strand{"forward": sequence.ForwardStrand, "reverse": sequence.ReverseStrand} {
+		found := orfs.Get(id)
+		if len(found) != 1 {
+			t.Fatalf("Expected 1 ORF in %s, got %d", id, len(found))
+		}
+		if found[0].Strand != strand || found[0].Start != 0 || found[0].End != 9 || found[0].ProteinSeq != "MK*" {
+			t.Errorf("Unexpected ORF in %s: %+v", id, found[0])
+		}
+	}
+}
+
+func TestFindORFs_Circular(t *testing.T) {
+	table, err := sequence.GetCodonTable(1)
+	if err != nil {
+		t.Fatalf("Unexpected error while getting codon table: %v", err)
+	}
+
+	set := NewRNASet([]bioio.Record{
+		{ID: "linear", Sequence: "GGGUAAAAAAUGCCC"},
+		{ID: "circular", Sequence: "GGGUAAAAAAUGCCC", Circular: true},
+	})
+
+	// the topology of each record wins over the option
+	for _, circular := range []bool{false, true} {
+		orfs, err := set.FindORFs(sequence.ORFOptions{CodonTable: &table, MinCodons: 1, Circular: circular})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if found := orfs.Get("linear"); len(found) != 0 {
+			t.Errorf("Circular=%t: expected no ORFs in linear record, got %+v", circular, found)
+		}
+		if found := orfs.Get("circular"); len(found) != 1 || found[0].Start != 9 || found[0].End != 6 {
+			t.Errorf("Circular=%t: expected one ORF across the origin in circular record, got %+v", circular, found)
+		}
+	}
+}